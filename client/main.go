@@ -7,11 +7,20 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
+	ossignal "os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	worker "github.com/sbui-dev/jobworker/data/proto"
+	"golang.org/x/term"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/alecthomas/kingpin/v2"
 )
@@ -19,37 +28,239 @@ import (
 const (
 	certFolder     = "../data/certs/"
 	serverCAPath   = "server_ca_cert.pem"
-	clientCertPath = "alice_cert.pem"
-	clientKeyPath  = "alice_key.pem"
+	clientCertPath = "%s_cert.pem"
+	clientKeyPath  = "%s_key.pem"
 	serverAddress  = "localhost:50005"
 )
 
 var (
 	app  = kingpin.New("jobclient", "A command-line job client.")
 	addr = app.Flag("addr", "The address to connect to").Default(serverAddress).String()
-	user = app.Flag("user", "Name of user: alice, bob, carl").Default("alice").String()
+	user = app.Flag("user", "Name of user whose certificate is used: alice, bob (auditor), carl (admin)").Default("alice").String()
 
-	start = app.Command("start", "Start a job")
-	cmd   = start.Arg("command", "command to run").Required().Strings()
+	start     = app.Command("start", "Start a job")
+	cpuQuota  = start.Flag("cpu-quota", "cpu time in microseconds allowed per cpu period").Int64()
+	cpuPeriod = start.Flag("cpu-period", "cpu period in microseconds").Int64()
+	memory    = start.Flag("memory", "maximum memory in bytes").Int64()
+	pidsMax   = start.Flag("pids", "maximum number of processes").Int64()
+	ioLimits  = start.Flag("io", "io limit of a device, i.e. \"8:0 wbps=1048576 wiops=120\"").Strings()
+	timeout   = start.Flag("timeout", "stop the job after this long, i.e. 10m").Duration()
+	detach    = start.Flag("detach", "print the job id and return without waiting for output").Short('d').Bool()
+	stdin     = start.Flag("stdin", "forward the local stdin to the job").Bool()
+	env       = start.Flag("env", "environment variable of the job, i.e. KEY=VAL").Short('e').Strings()
+	cleanEnv  = start.Flag("clean-env", "start the job with only the --env variables").Bool()
+	cwd       = start.Flag("cwd", "absolute working directory of the job").String()
+	umask     = start.Flag("umask", "octal file mode creation mask of the job, i.e. 022").String()
+	isolate   = start.Flag("isolate", "run the job in a new namespace: pid, mount, uts, ipc, net or all").Enums("pid", "mount", "uts", "ipc", "net", "all")
+	rootfs    = start.Flag("rootfs", "name of a server image to run the job in instead of the host's root filesystem").String()
+	seccomp   = start.Flag("seccomp", "seccomp profile of the job: default, network-off or strict").Enum("default", "network-off", "strict")
+	cmd       = start.Arg("command", "command to run").Required().Strings()
 
-	stop   = app.Command("stop", "Stop a job")
-	stopid = stop.Arg("id", "job id").Required().String()
+	execJob         = app.Command("exec", "Run a job in a terminal attached to the local one, i.e. exec -it -- top")
+	execInteractive = execJob.Flag("interactive", "forward keystrokes to the job, the local terminal is put in raw mode").Short('i').Bool()
+	execTTY         = execJob.Flag("tty", "run the job in a pseudo-terminal").Short('t').Bool()
+	execCmd         = execJob.Arg("command", "command to run").Required().Strings()
+
+	stop        = app.Command("stop", "Stop a job")
+	stopSignal  = stop.Flag("signal", "signal sent to the job first, i.e. SIGINT").Default("SIGTERM").String()
+	stopTimeout = stop.Flag("timeout", "time the job has to exit before it is killed, i.e. 30s").Duration()
+	stopid      = stop.Arg("id", "job id").Required().String()
+
+	signal       = app.Command("signal", "Send a signal to a running job")
+	signalid     = signal.Arg("id", "job id").Required().String()
+	signalSignal = signal.Arg("signal", "signal name, i.e. SIGHUP").Required().String()
+
+	pause   = app.Command("pause", "Freeze a running job")
+	pauseid = pause.Arg("id", "job id").Required().String()
+
+	resume   = app.Command("resume", "Thaw a paused job")
+	resumeid = resume.Arg("id", "job id").Required().String()
 
 	query   = app.Command("query", "Query a job")
 	queryid = query.Arg("id", "job id").Required().String()
+
+	logs       = app.Command("logs", "Print the output of a job")
+	logsFollow = logs.Flag("follow", "keep printing output until the job ends").Short('f').Bool()
+	logsOffset = logs.Flag("offset", "byte offset in the output to start from").Int64()
+	logsTail   = logs.Flag("tail", "print only the last lines of output").Int32()
+	logsid     = logs.Arg("id", "job id").Required().String()
+
+	list          = app.Command("list", "List your jobs")
+	listStates    = list.Flag("state", "only list jobs in this state, i.e. running").Strings()
+	listCommand   = list.Flag("command", "only list jobs whose command contains this string").String()
+	listSince     = list.Flag("since", "only list jobs created since this time, RFC3339 or a duration ago, i.e. 1h").String()
+	listUntil     = list.Flag("until", "only list jobs created before this time, RFC3339 or a duration ago").String()
+	listPageSize  = list.Flag("page-size", "number of jobs per page").Int32()
+	listPageToken = list.Flag("page-token", "token printed after the previous page").String()
+	listAll       = list.Flag("all", "list every user's jobs, admins and auditors only").Bool()
 )
 
+// parseIOLimit helper func to parse a device limit in the io.max format
+func parseIOLimit(value string) (*worker.IOLimit, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty io limit")
+	}
+
+	limit := &worker.IOLimit{Device: fields[0]}
+	for _, field := range fields[1:] {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid io limit %q", field)
+		}
+		n, err := strconv.ParseInt(kv[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid io limit %q: %v", field, err)
+		}
+		switch kv[0] {
+		case "rbps":
+			limit.ReadBps = n
+		case "wbps":
+			limit.WriteBps = n
+		case "riops":
+			limit.ReadIops = n
+		case "wiops":
+			limit.WriteIops = n
+		default:
+			return nil, fmt.Errorf("unknown io limit %q", kv[0])
+		}
+	}
+	return limit, nil
+}
+
+// startRequest helper func to build a start request from the start flags
+func startRequest(message []string) *worker.WorkerStartRequest {
+	limits := &worker.ResourceLimits{
+		CpuQuota:    *cpuQuota,
+		CpuPeriod:   *cpuPeriod,
+		MemoryBytes: *memory,
+		PidsMax:     *pidsMax,
+	}
+	for _, value := range *ioLimits {
+		limit, err := parseIOLimit(value)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		limits.Io = append(limits.Io, limit)
+	}
+
+	return &worker.WorkerStartRequest{
+		Command:        message,
+		Limits:         limits,
+		TimeoutSeconds: durationSeconds(*timeout),
+		Stdin:          *stdin,
+		Env:            *env,
+		CleanEnv:       *cleanEnv,
+		Cwd:            *cwd,
+		Umask:          *umask,
+		Namespaces:     namespacesRequest(*isolate),
+		Rootfs:         *rootfs,
+		Seccomp:        *seccomp,
+	}
+}
+
+// namespacesRequest helper func to convert the --isolate values
+func namespacesRequest(names []string) *worker.Namespaces {
+	namespaces := &worker.Namespaces{}
+	for _, name := range names {
+		switch name {
+		case "pid":
+			namespaces.Pid = true
+		case "mount":
+			namespaces.Mount = true
+		case "uts":
+			namespaces.Uts = true
+		case "ipc":
+			namespaces.Ipc = true
+		case "net":
+			namespaces.Net = true
+		case "all":
+			namespaces = &worker.Namespaces{Pid: true, Mount: true, Uts: true, Ipc: true, Net: true}
+		}
+	}
+	return namespaces
+}
+
+// submitJob starts a job in the background and prints only its id so it can be used in scripts
+func submitJob(client worker.WorkerClient, message []string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resp, err := client.JobSubmit(ctx, startRequest(message))
+	if err != nil {
+		log.Fatalf("client = %v: ", err)
+	}
+	if *stdin {
+		if err := forwardStdin(client, resp.JobId); err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
+	fmt.Println(resp.JobId)
+}
+
+// startWithStdin starts a job, forwards the local stdin to it and prints its output.
+// The job may not print anything before it has read all of its input, so the
+// output is followed with JobLogs instead of waiting on JobStart
+func startWithStdin(client worker.WorkerClient, message []string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resp, err := client.JobSubmit(ctx, startRequest(message))
+	if err != nil {
+		log.Fatalf("client = %v: ", err)
+	}
+	log.Printf("Job ID: %s", resp.JobId)
+
+	go func() {
+		if err := forwardStdin(client, resp.JobId); err != nil {
+			log.Printf("%v", err)
+		}
+	}()
+	printLogs(client, &worker.WorkerLogsRequest{JobId: resp.JobId, Follow: true})
+}
+
+// forwardStdin sends the local stdin to a job and closes the job's stdin at EOF
+func forwardStdin(client worker.WorkerClient, jobID string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.JobStdin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open stdin of job: %v", err)
+	}
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if n > 0 {
+			if sendErr := stream.Send(&worker.WorkerStdinRequest{JobId: jobID, Data: buf[:n]}); sendErr != nil {
+				break
+			}
+		}
+		if err == io.EOF {
+			stream.Send(&worker.WorkerStdinRequest{JobId: jobID, Eof: true})
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %v", err)
+		}
+	}
+	// a failed send is reported by CloseAndRecv with the server's error
+	if _, err := stream.CloseAndRecv(); err != nil {
+		return fmt.Errorf("failed to write stdin of job: %v", err)
+	}
+	return nil
+}
+
 func startJob(client worker.WorkerClient, message []string) {
-	fmt.Println("sending job")
+	log.Printf("sending job")
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	resp, err := client.JobStart(ctx, &worker.WorkerStartRequest{Command: message})
+	resp, err := client.JobStart(ctx, startRequest(message))
 	if err != nil {
 		log.Fatalf("client = %v: ", err)
 	}
 	done := make(chan struct{})
 
 	go func() {
+		jobID := ""
 		for {
 			resp, err := resp.Recv()
 			if err == io.EOF {
@@ -59,17 +270,163 @@ func startJob(client worker.WorkerClient, message []string) {
 			if err != nil {
 				log.Fatalf("cannot receive %v", err)
 			}
-			log.Printf("Resp received:%s:  %s", resp.JobId, resp.Log)
+			if jobID == "" {
+				jobID = resp.JobId
+				log.Printf("Job ID: %s", jobID)
+			}
+			if resp.Stream == worker.OutputStream_OUTPUT_STREAM_STDERR {
+				os.Stderr.Write(resp.Data)
+			} else {
+				os.Stdout.Write(resp.Data)
+			}
 		}
 	}()
 
 	<-done
 }
 
+// runInTerminal starts a job in a pseudo-terminal, attaches to it until it ends
+// and returns its exit code
+func runInTerminal(client worker.WorkerClient, message []string) int {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resp, err := client.JobSubmit(ctx, &worker.WorkerStartRequest{
+		Command:      message,
+		Tty:          true,
+		TerminalSize: localTerminalSize(),
+	})
+	if err != nil {
+		log.Fatalf("client = %v: ", err)
+	}
+	log.Printf("Job ID: %s", resp.JobId)
+
+	if err := attachJob(client, resp.JobId, *execInteractive); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	result, err := client.JobQuery(ctx, &worker.WorkerQueryRequest{JobId: resp.JobId})
+	if err != nil {
+		log.Fatalf("client = %v: ", err)
+	}
+	if result.ExitCode < 0 {
+		log.Printf("job %s: %s %s", result.Status, result.Reason, result.Signal)
+		return 1
+	}
+	return int(result.ExitCode)
+}
+
+// attachJob connects the local terminal to a job's terminal until the job ends.
+// When interactive the local terminal is in raw mode meanwhile, so keys like ctrl-C
+// reach the job instead of the client
+func attachJob(client worker.WorkerClient, jobID string, interactive bool) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.JobAttach(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to attach to job: %v", err)
+	}
+
+	// a stream is not safe for concurrent sends, everything goes through one goroutine
+	requests := make(chan *worker.WorkerAttachRequest, 16)
+	go func() {
+		for req := range requests {
+			if err := stream.Send(req); err != nil {
+				return
+			}
+		}
+	}()
+	requests <- &worker.WorkerAttachRequest{JobId: jobID, Resize: localTerminalSize()}
+
+	winch := make(chan os.Signal, 1)
+	ossignal.Notify(winch, syscall.SIGWINCH)
+	defer ossignal.Stop(winch)
+	go func() {
+		for range winch {
+			requests <- &worker.WorkerAttachRequest{Resize: localTerminalSize()}
+		}
+	}()
+
+	if interactive {
+		fd := int(os.Stdin.Fd())
+		if term.IsTerminal(fd) {
+			state, err := term.MakeRaw(fd)
+			if err != nil {
+				return fmt.Errorf("failed to set raw mode: %v", err)
+			}
+			defer term.Restore(fd, state)
+		}
+		go func() {
+			buf := make([]byte, 32*1024)
+			for {
+				n, err := os.Stdin.Read(buf)
+				if n > 0 {
+					data := make([]byte, n)
+					copy(data, buf[:n])
+					requests <- &worker.WorkerAttachRequest{Data: data}
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cannot receive %v", err)
+		}
+		os.Stdout.Write(resp.Data)
+	}
+}
+
+// localTerminalSize returns the size of the local terminal, nil when stdout is not a terminal
+func localTerminalSize() *worker.TerminalSize {
+	cols, rows, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return nil
+	}
+	return &worker.TerminalSize{Rows: uint32(rows), Cols: uint32(cols)}
+}
+
 func stopJob(client worker.WorkerClient, jobID string) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	_, err := client.JobStop(ctx, &worker.WorkerStopRequest{JobId: jobID})
+	_, err := client.JobStop(ctx, &worker.WorkerStopRequest{
+		JobId:          jobID,
+		Signal:         *stopSignal,
+		TimeoutSeconds: durationSeconds(*stopTimeout),
+	})
+	if err != nil {
+		log.Fatalf("client = %v: ", err)
+	}
+}
+
+func signalJob(client worker.WorkerClient, jobID string, sig string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := client.JobSignal(ctx, &worker.WorkerSignalRequest{JobId: jobID, Signal: sig})
+	if err != nil {
+		log.Fatalf("client = %v: ", err)
+	}
+}
+
+func pauseJob(client worker.WorkerClient, jobID string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := client.JobPause(ctx, &worker.WorkerPauseRequest{JobId: jobID})
+	if err != nil {
+		log.Fatalf("client = %v: ", err)
+	}
+}
+
+func resumeJob(client worker.WorkerClient, jobID string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := client.JobResume(ctx, &worker.WorkerResumeRequest{JobId: jobID})
 	if err != nil {
 		log.Fatalf("client = %v: ", err)
 	}
@@ -83,10 +440,141 @@ func queryJob(client worker.WorkerClient, jobID string) {
 		log.Fatalf("client = %v: ", err)
 	}
 	log.Printf("Job status is: %s", resp.Status)
+	for _, change := range resp.History {
+		log.Printf("  %s %s", change.Time.AsTime().Local().Format(time.RFC3339), change.State)
+	}
+	if resp.StartTime != nil {
+		log.Printf("Started: %s", resp.StartTime.AsTime().Local())
+	}
+	if resp.Reason == worker.TerminationReason_TERMINATION_REASON_NONE {
+		return
+	}
+	log.Printf("Ended: %s", resp.EndTime.AsTime().Local())
+	log.Printf("Reason: %s", resp.Reason)
+	log.Printf("Exit code: %d", resp.ExitCode)
+	if resp.Signal != "" {
+		log.Printf("Signal: %s", resp.Signal)
+	}
+}
+
+func logsJob(client worker.WorkerClient, jobID string) {
+	printLogs(client, &worker.WorkerLogsRequest{
+		JobId:  jobID,
+		Follow: *logsFollow,
+		Offset: *logsOffset,
+		Tail:   *logsTail,
+	})
+}
+
+// printLogs writes the output selected by req to stdout and stderr
+func printLogs(client worker.WorkerClient, req *worker.WorkerLogsRequest) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resp, err := client.JobLogs(ctx, req)
+	if err != nil {
+		log.Fatalf("client = %v: ", err)
+	}
+
+	for {
+		out, err := resp.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			log.Fatalf("cannot receive %v", err)
+		}
+		if out.Stream == worker.OutputStream_OUTPUT_STREAM_STDERR {
+			os.Stderr.Write(out.Data)
+		} else {
+			os.Stdout.Write(out.Data)
+		}
+	}
+}
+
+// parseTime helper func to parse a time in RFC3339 or a duration before now
+// durationSeconds helper func to send a duration in whole seconds, rounded up
+// since the server takes 0 as no timeout at all
+func durationSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
+
+func parseTime(value string) (*timestamppb.Timestamp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamppb.New(t), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %q, expected RFC3339 or a duration", value)
+	}
+	return timestamppb.New(time.Now().Add(-d)), nil
+}
+
+// formatTime helper func to print an optional timestamp in the list table
+func formatTime(t *timestamppb.Timestamp) string {
+	if t == nil {
+		return "-"
+	}
+	return t.AsTime().Local().Format(time.RFC3339)
+}
+
+func listJobs(client worker.WorkerClient) {
+	req := &worker.WorkerListRequest{
+		Command:   *listCommand,
+		PageSize:  *listPageSize,
+		PageToken: *listPageToken,
+		AllUsers:  *listAll,
+	}
+	for _, name := range *listStates {
+		state, ok := worker.JobState_value["JOB_STATE_"+strings.ToUpper(name)]
+		if !ok {
+			log.Fatalf("unknown job state %q", name)
+		}
+		req.States = append(req.States, worker.JobState(state))
+	}
+	var err error
+	if req.Since, err = parseTime(*listSince); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if req.Until, err = parseTime(*listUntil); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resp, err := client.JobList(ctx, req)
+	if err != nil {
+		log.Fatalf("client = %v: ", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := "ID\tSTATE\tEXIT\tCREATED\tSTARTED\tENDED\tCOMMAND"
+	if *listAll {
+		header = "USER\t" + header
+	}
+	fmt.Fprintln(w, header)
+	for _, job := range resp.Jobs {
+		exitCode := "-"
+		if job.EndTime != nil {
+			exitCode = strconv.Itoa(int(job.ExitCode))
+		}
+		state := strings.ToLower(strings.TrimPrefix(job.State.String(), "JOB_STATE_"))
+		if *listAll {
+			fmt.Fprintf(w, "%s\t", job.Username)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", job.JobId, state, exitCode,
+			formatTime(job.CreateTime), formatTime(job.StartTime), formatTime(job.EndTime), strings.Join(job.Command, " "))
+	}
+	w.Flush()
+	if resp.NextPageToken != "" {
+		log.Printf("more jobs with --page-token %s", resp.NextPageToken)
+	}
 }
 
 func setupTLSConfig() (*tls.Config, error) {
-	cert, err := tls.LoadX509KeyPair(certFolder+clientCertPath, certFolder+clientKeyPath)
+	cert, err := tls.LoadX509KeyPair(certFolder+fmt.Sprintf(clientCertPath, *user), certFolder+fmt.Sprintf(clientKeyPath, *user))
 	if err != nil {
 		// todo fix errors
 		log.Fatalf("failed to load client cert: %v", err)
@@ -110,7 +598,10 @@ func setupTLSConfig() (*tls.Config, error) {
 }
 
 func main() {
-	fmt.Println("setting up tls")
+	// flags are parsed first, the certificate and address depend on them
+	command := kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	log.Printf("setting up tls")
 	tlsConfig, err := setupTLSConfig()
 	if err != nil {
 		log.Fatalf("failed to setup tls config %v", err)
@@ -120,22 +611,51 @@ func main() {
 	if *addr != "" {
 		serverAddr = fmt.Sprintf("passthrough:///%s", *addr)
 	}
-	fmt.Printf("setting up conn with %s\n", serverAddr)
+	log.Printf("setting up conn with %s", serverAddr)
 	conn, err := grpc.Dial(serverAddr, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	if err != nil {
 		log.Fatalf("did not connect: %v", err)
 	}
 	defer conn.Close()
 
-	fmt.Println("setting up client")
+	log.Printf("setting up client")
 	workerClient := worker.NewWorkerClient(conn)
 
-	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	switch command {
 	case start.FullCommand():
-		startJob(workerClient, *cmd)
+		switch {
+		case *detach:
+			submitJob(workerClient, *cmd)
+		case *stdin:
+			startWithStdin(workerClient, *cmd)
+		default:
+			startJob(workerClient, *cmd)
+		}
+	case execJob.FullCommand():
+		if !*execTTY {
+			app.Fatalf("exec needs -t, use start --stdin to run a job without a terminal")
+		}
+		command := *execCmd
+		// kingpin keeps the -- separator when it follows bundled flags like -it
+		if command[0] == "--" && len(command) > 1 {
+			command = command[1:]
+		}
+		code := runInTerminal(workerClient, command)
+		conn.Close()
+		os.Exit(code)
 	case stop.FullCommand():
 		stopJob(workerClient, *stopid)
+	case signal.FullCommand():
+		signalJob(workerClient, *signalid, *signalSignal)
+	case pause.FullCommand():
+		pauseJob(workerClient, *pauseid)
+	case resume.FullCommand():
+		resumeJob(workerClient, *resumeid)
 	case query.FullCommand():
 		queryJob(workerClient, *queryid)
+	case logs.FullCommand():
+		logsJob(workerClient, *logsid)
+	case list.FullCommand():
+		listJobs(workerClient)
 	}
 }
@@ -22,14 +22,14 @@ openssl req -new                                      \
   -config ./config.cnf                                \
   -reqexts job-server
 
-# Sign server cert
+# Sign server cert, serials come from the CA's serial file so no two certs share one
 openssl x509 -req           \
   -in server_csr.pem        \
   -CAkey server_ca_key.pem  \
   -CA server_ca_cert.pem    \
   -days 365                 \
   -out server_cert.pem      \
-  -set_serial 1             \
+  -CAcreateserial           \
   -extfile ./config.cnf     \
   -extensions job-server   \
   -sha256
@@ -49,26 +49,36 @@ openssl req -x509                                         \
   -extensions test_ca                                     \
   -sha256
 
-# Generate client certs
-openssl genrsa -out alice_key.pem 4096
-openssl req -new                                       \
-  -key alice_key.pem                                   \
-  -days 365                                            \
-  -out alice_csr.pem                                   \
-  -subj /C=US/ST=CA/L=SF/O=JobWorker/CN=alice/         \
-  -config ./config.cnf                                 \
-  -reqexts test_client
+# Generate client certs, the organizational unit is the user's role: bob is
+# an auditor, carl an admin and alice a standard user
+for user in alice bob:auditor carl:admin; do
+  name=${user%%:*}
+  ou=""
+  if [ "$name" != "$user" ]; then
+    ou="OU=${user#*:}/"
+  fi
 
-openssl x509 -req           \
-  -in alice_csr.pem         \
-  -CAkey client_ca_key.pem  \
-  -CA client_ca_cert.pem    \
-  -days 365                 \
-  -out alice_cert.pem       \
-  -set_serial 1             \
-  -extfile ./config.cnf     \
-  -extensions test_client   \
-  -sha256
-openssl verify -verbose -CAfile client_ca_cert.pem alice_cert.pem
+  openssl genrsa -out ${name}_key.pem 4096
+  openssl req -new                                        \
+    -key ${name}_key.pem                                  \
+    -days 365                                             \
+    -out ${name}_csr.pem                                  \
+    -subj /C=US/ST=CA/L=SF/O=JobWorker/${ou}CN=${name}/   \
+    -config ./config.cnf                                  \
+    -reqexts test_client
+
+  openssl x509 -req           \
+    -in ${name}_csr.pem       \
+    -CAkey client_ca_key.pem  \
+    -CA client_ca_cert.pem    \
+    -days 365                 \
+    -out ${name}_cert.pem     \
+    -CAcreateserial           \
+    -extfile ./config.cnf     \
+    -extensions test_client   \
+    -sha256
+  openssl verify -verbose -CAfile client_ca_cert.pem ${name}_cert.pem
+done
 
-rm *_csr.pem
+# the serial files only matter while signing, every run starts from a random serial
+rm *_csr.pem *.srl
@@ -9,6 +9,7 @@ package jobworker_data
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -20,16 +21,201 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-type WorkerStartRequest struct {
+type OutputStream int32
+
+const (
+	OutputStream_OUTPUT_STREAM_STDOUT OutputStream = 0
+	OutputStream_OUTPUT_STREAM_STDERR OutputStream = 1
+)
+
+// Enum value maps for OutputStream.
+var (
+	OutputStream_name = map[int32]string{
+		0: "OUTPUT_STREAM_STDOUT",
+		1: "OUTPUT_STREAM_STDERR",
+	}
+	OutputStream_value = map[string]int32{
+		"OUTPUT_STREAM_STDOUT": 0,
+		"OUTPUT_STREAM_STDERR": 1,
+	}
+)
+
+func (x OutputStream) Enum() *OutputStream {
+	p := new(OutputStream)
+	*p = x
+	return p
+}
+
+func (x OutputStream) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OutputStream) Descriptor() protoreflect.EnumDescriptor {
+	return file_jobworker_proto_enumTypes[0].Descriptor()
+}
+
+func (OutputStream) Type() protoreflect.EnumType {
+	return &file_jobworker_proto_enumTypes[0]
+}
+
+func (x OutputStream) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OutputStream.Descriptor instead.
+func (OutputStream) EnumDescriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{0}
+}
+
+type JobState int32
+
+const (
+	JobState_JOB_STATE_UNSPECIFIED JobState = 0
+	JobState_JOB_STATE_PENDING     JobState = 1
+	JobState_JOB_STATE_RUNNING     JobState = 2
+	JobState_JOB_STATE_SUCCEEDED   JobState = 3
+	JobState_JOB_STATE_FAILED      JobState = 4
+	JobState_JOB_STATE_STOPPING    JobState = 5
+	JobState_JOB_STATE_STOPPED     JobState = 6
+	JobState_JOB_STATE_KILLED      JobState = 7
+	JobState_JOB_STATE_LOST        JobState = 8
+	JobState_JOB_STATE_PAUSED      JobState = 9
+)
+
+// Enum value maps for JobState.
+var (
+	JobState_name = map[int32]string{
+		0: "JOB_STATE_UNSPECIFIED",
+		1: "JOB_STATE_PENDING",
+		2: "JOB_STATE_RUNNING",
+		3: "JOB_STATE_SUCCEEDED",
+		4: "JOB_STATE_FAILED",
+		5: "JOB_STATE_STOPPING",
+		6: "JOB_STATE_STOPPED",
+		7: "JOB_STATE_KILLED",
+		8: "JOB_STATE_LOST",
+		9: "JOB_STATE_PAUSED",
+	}
+	JobState_value = map[string]int32{
+		"JOB_STATE_UNSPECIFIED": 0,
+		"JOB_STATE_PENDING":     1,
+		"JOB_STATE_RUNNING":     2,
+		"JOB_STATE_SUCCEEDED":   3,
+		"JOB_STATE_FAILED":      4,
+		"JOB_STATE_STOPPING":    5,
+		"JOB_STATE_STOPPED":     6,
+		"JOB_STATE_KILLED":      7,
+		"JOB_STATE_LOST":        8,
+		"JOB_STATE_PAUSED":      9,
+	}
+)
+
+func (x JobState) Enum() *JobState {
+	p := new(JobState)
+	*p = x
+	return p
+}
+
+func (x JobState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobState) Descriptor() protoreflect.EnumDescriptor {
+	return file_jobworker_proto_enumTypes[1].Descriptor()
+}
+
+func (JobState) Type() protoreflect.EnumType {
+	return &file_jobworker_proto_enumTypes[1]
+}
+
+func (x JobState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobState.Descriptor instead.
+func (JobState) EnumDescriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{1}
+}
+
+type TerminationReason int32
+
+const (
+	TerminationReason_TERMINATION_REASON_NONE         TerminationReason = 0 // job has not ended
+	TerminationReason_TERMINATION_REASON_EXITED       TerminationReason = 1
+	TerminationReason_TERMINATION_REASON_SIGNALED     TerminationReason = 2
+	TerminationReason_TERMINATION_REASON_STOPPED      TerminationReason = 3
+	TerminationReason_TERMINATION_REASON_OOM_KILLED   TerminationReason = 4
+	TerminationReason_TERMINATION_REASON_TIMED_OUT    TerminationReason = 5
+	TerminationReason_TERMINATION_REASON_START_FAILED TerminationReason = 6
+	TerminationReason_TERMINATION_REASON_LOST         TerminationReason = 7 // the server restarted while the job was running
+)
+
+// Enum value maps for TerminationReason.
+var (
+	TerminationReason_name = map[int32]string{
+		0: "TERMINATION_REASON_NONE",
+		1: "TERMINATION_REASON_EXITED",
+		2: "TERMINATION_REASON_SIGNALED",
+		3: "TERMINATION_REASON_STOPPED",
+		4: "TERMINATION_REASON_OOM_KILLED",
+		5: "TERMINATION_REASON_TIMED_OUT",
+		6: "TERMINATION_REASON_START_FAILED",
+		7: "TERMINATION_REASON_LOST",
+	}
+	TerminationReason_value = map[string]int32{
+		"TERMINATION_REASON_NONE":         0,
+		"TERMINATION_REASON_EXITED":       1,
+		"TERMINATION_REASON_SIGNALED":     2,
+		"TERMINATION_REASON_STOPPED":      3,
+		"TERMINATION_REASON_OOM_KILLED":   4,
+		"TERMINATION_REASON_TIMED_OUT":    5,
+		"TERMINATION_REASON_START_FAILED": 6,
+		"TERMINATION_REASON_LOST":         7,
+	}
+)
+
+func (x TerminationReason) Enum() *TerminationReason {
+	p := new(TerminationReason)
+	*p = x
+	return p
+}
+
+func (x TerminationReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TerminationReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_jobworker_proto_enumTypes[2].Descriptor()
+}
+
+func (TerminationReason) Type() protoreflect.EnumType {
+	return &file_jobworker_proto_enumTypes[2]
+}
+
+func (x TerminationReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TerminationReason.Descriptor instead.
+func (TerminationReason) EnumDescriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{2}
+}
+
+// IOLimit limits a block device, zero means no limit
+type IOLimit struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Command []string `protobuf:"bytes,1,rep,name=command,proto3" json:"command,omitempty"`
+	Device    string `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"` // major:minor, i.e. 8:0
+	ReadBps   int64  `protobuf:"varint,2,opt,name=read_bps,json=readBps,proto3" json:"read_bps,omitempty"`
+	WriteBps  int64  `protobuf:"varint,3,opt,name=write_bps,json=writeBps,proto3" json:"write_bps,omitempty"`
+	ReadIops  int64  `protobuf:"varint,4,opt,name=read_iops,json=readIops,proto3" json:"read_iops,omitempty"`
+	WriteIops int64  `protobuf:"varint,5,opt,name=write_iops,json=writeIops,proto3" json:"write_iops,omitempty"`
 }
 
-func (x *WorkerStartRequest) Reset() {
-	*x = WorkerStartRequest{}
+func (x *IOLimit) Reset() {
+	*x = IOLimit{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_jobworker_proto_msgTypes[0]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -37,13 +223,13 @@ func (x *WorkerStartRequest) Reset() {
 	}
 }
 
-func (x *WorkerStartRequest) String() string {
+func (x *IOLimit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WorkerStartRequest) ProtoMessage() {}
+func (*IOLimit) ProtoMessage() {}
 
-func (x *WorkerStartRequest) ProtoReflect() protoreflect.Message {
+func (x *IOLimit) ProtoReflect() protoreflect.Message {
 	mi := &file_jobworker_proto_msgTypes[0]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -55,28 +241,61 @@ func (x *WorkerStartRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WorkerStartRequest.ProtoReflect.Descriptor instead.
-func (*WorkerStartRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use IOLimit.ProtoReflect.Descriptor instead.
+func (*IOLimit) Descriptor() ([]byte, []int) {
 	return file_jobworker_proto_rawDescGZIP(), []int{0}
 }
 
-func (x *WorkerStartRequest) GetCommand() []string {
+func (x *IOLimit) GetDevice() string {
 	if x != nil {
-		return x.Command
+		return x.Device
 	}
-	return nil
+	return ""
 }
 
-type WorkerStopRequest struct {
+func (x *IOLimit) GetReadBps() int64 {
+	if x != nil {
+		return x.ReadBps
+	}
+	return 0
+}
+
+func (x *IOLimit) GetWriteBps() int64 {
+	if x != nil {
+		return x.WriteBps
+	}
+	return 0
+}
+
+func (x *IOLimit) GetReadIops() int64 {
+	if x != nil {
+		return x.ReadIops
+	}
+	return 0
+}
+
+func (x *IOLimit) GetWriteIops() int64 {
+	if x != nil {
+		return x.WriteIops
+	}
+	return 0
+}
+
+// ResourceLimits of the job's cgroup, unset fields use the server defaults
+type ResourceLimits struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	CpuQuota    int64      `protobuf:"varint,1,opt,name=cpu_quota,json=cpuQuota,proto3" json:"cpu_quota,omitempty"`
+	CpuPeriod   int64      `protobuf:"varint,2,opt,name=cpu_period,json=cpuPeriod,proto3" json:"cpu_period,omitempty"`
+	MemoryBytes int64      `protobuf:"varint,3,opt,name=memory_bytes,json=memoryBytes,proto3" json:"memory_bytes,omitempty"`
+	Io          []*IOLimit `protobuf:"bytes,4,rep,name=io,proto3" json:"io,omitempty"`
+	PidsMax     int64      `protobuf:"varint,5,opt,name=pids_max,json=pidsMax,proto3" json:"pids_max,omitempty"`
 }
 
-func (x *WorkerStopRequest) Reset() {
-	*x = WorkerStopRequest{}
+func (x *ResourceLimits) Reset() {
+	*x = ResourceLimits{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_jobworker_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -84,13 +303,13 @@ func (x *WorkerStopRequest) Reset() {
 	}
 }
 
-func (x *WorkerStopRequest) String() string {
+func (x *ResourceLimits) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WorkerStopRequest) ProtoMessage() {}
+func (*ResourceLimits) ProtoMessage() {}
 
-func (x *WorkerStopRequest) ProtoReflect() protoreflect.Message {
+func (x *ResourceLimits) ProtoReflect() protoreflect.Message {
 	mi := &file_jobworker_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -102,28 +321,68 @@ func (x *WorkerStopRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WorkerStopRequest.ProtoReflect.Descriptor instead.
-func (*WorkerStopRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResourceLimits.ProtoReflect.Descriptor instead.
+func (*ResourceLimits) Descriptor() ([]byte, []int) {
 	return file_jobworker_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *WorkerStopRequest) GetJobId() string {
+func (x *ResourceLimits) GetCpuQuota() int64 {
 	if x != nil {
-		return x.JobId
+		return x.CpuQuota
 	}
-	return ""
+	return 0
 }
 
-type WorkerQueryRequest struct {
+func (x *ResourceLimits) GetCpuPeriod() int64 {
+	if x != nil {
+		return x.CpuPeriod
+	}
+	return 0
+}
+
+func (x *ResourceLimits) GetMemoryBytes() int64 {
+	if x != nil {
+		return x.MemoryBytes
+	}
+	return 0
+}
+
+func (x *ResourceLimits) GetIo() []*IOLimit {
+	if x != nil {
+		return x.Io
+	}
+	return nil
+}
+
+func (x *ResourceLimits) GetPidsMax() int64 {
+	if x != nil {
+		return x.PidsMax
+	}
+	return 0
+}
+
+type WorkerStartRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Command        []string        `protobuf:"bytes,1,rep,name=command,proto3" json:"command,omitempty"`
+	Limits         *ResourceLimits `protobuf:"bytes,2,opt,name=limits,proto3" json:"limits,omitempty"`
+	TimeoutSeconds int64           `protobuf:"varint,3,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"` // zero for no timeout
+	Stdin          bool            `protobuf:"varint,4,opt,name=stdin,proto3" json:"stdin,omitempty"`                                         // stdin is written with JobStdin instead of reading /dev/null
+	Tty            bool            `protobuf:"varint,5,opt,name=tty,proto3" json:"tty,omitempty"`                                             // run in a pseudo-terminal used with JobAttach, implies stdin
+	TerminalSize   *TerminalSize   `protobuf:"bytes,6,opt,name=terminal_size,json=terminalSize,proto3" json:"terminal_size,omitempty"`        // initial size of the pseudo-terminal
+	Env            []string        `protobuf:"bytes,7,rep,name=env,proto3" json:"env,omitempty"`                                              // KEY=VALUE added to the server's environment
+	CleanEnv       bool            `protobuf:"varint,8,opt,name=clean_env,json=cleanEnv,proto3" json:"clean_env,omitempty"`                   // start from an empty environment
+	Cwd            string          `protobuf:"bytes,9,opt,name=cwd,proto3" json:"cwd,omitempty"`                                              // absolute working directory, the server's when empty
+	Umask          string          `protobuf:"bytes,10,opt,name=umask,proto3" json:"umask,omitempty"`                                         // octal, i.e. 022, the server's when empty
+	Namespaces     *Namespaces     `protobuf:"bytes,11,opt,name=namespaces,proto3" json:"namespaces,omitempty"`                               // added to the namespaces the server's policy requires
+	Rootfs         string          `protobuf:"bytes,12,opt,name=rootfs,proto3" json:"rootfs,omitempty"`                                       // name of a server image the job runs in, the host's root filesystem when empty
+	Seccomp        string          `protobuf:"bytes,13,opt,name=seccomp,proto3" json:"seccomp,omitempty"`                                     // seccomp profile: default, network-off or strict, the server's policy may require a stricter one
 }
 
-func (x *WorkerQueryRequest) Reset() {
-	*x = WorkerQueryRequest{}
+func (x *WorkerStartRequest) Reset() {
+	*x = WorkerStartRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_jobworker_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -131,13 +390,13 @@ func (x *WorkerQueryRequest) Reset() {
 	}
 }
 
-func (x *WorkerQueryRequest) String() string {
+func (x *WorkerStartRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WorkerQueryRequest) ProtoMessage() {}
+func (*WorkerStartRequest) ProtoMessage() {}
 
-func (x *WorkerQueryRequest) ProtoReflect() protoreflect.Message {
+func (x *WorkerStartRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_jobworker_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -149,29 +408,117 @@ func (x *WorkerQueryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WorkerQueryRequest.ProtoReflect.Descriptor instead.
-func (*WorkerQueryRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use WorkerStartRequest.ProtoReflect.Descriptor instead.
+func (*WorkerStartRequest) Descriptor() ([]byte, []int) {
 	return file_jobworker_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *WorkerQueryRequest) GetJobId() string {
+func (x *WorkerStartRequest) GetCommand() []string {
 	if x != nil {
-		return x.JobId
+		return x.Command
+	}
+	return nil
+}
+
+func (x *WorkerStartRequest) GetLimits() *ResourceLimits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+func (x *WorkerStartRequest) GetTimeoutSeconds() int64 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *WorkerStartRequest) GetStdin() bool {
+	if x != nil {
+		return x.Stdin
+	}
+	return false
+}
+
+func (x *WorkerStartRequest) GetTty() bool {
+	if x != nil {
+		return x.Tty
+	}
+	return false
+}
+
+func (x *WorkerStartRequest) GetTerminalSize() *TerminalSize {
+	if x != nil {
+		return x.TerminalSize
+	}
+	return nil
+}
+
+func (x *WorkerStartRequest) GetEnv() []string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *WorkerStartRequest) GetCleanEnv() bool {
+	if x != nil {
+		return x.CleanEnv
+	}
+	return false
+}
+
+func (x *WorkerStartRequest) GetCwd() string {
+	if x != nil {
+		return x.Cwd
 	}
 	return ""
 }
 
-type WorkerStartResponse struct {
+func (x *WorkerStartRequest) GetUmask() string {
+	if x != nil {
+		return x.Umask
+	}
+	return ""
+}
+
+func (x *WorkerStartRequest) GetNamespaces() *Namespaces {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+func (x *WorkerStartRequest) GetRootfs() string {
+	if x != nil {
+		return x.Rootfs
+	}
+	return ""
+}
+
+func (x *WorkerStartRequest) GetSeccomp() string {
+	if x != nil {
+		return x.Seccomp
+	}
+	return ""
+}
+
+// Namespaces isolating a job from the host, each set field gives the job a new one
+type Namespaces struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	Log   string `protobuf:"bytes,2,opt,name=log,proto3" json:"log,omitempty"`
+	Pid   bool `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"` // only the job's processes are visible, with a private /proc
+	Mount bool `protobuf:"varint,2,opt,name=mount,proto3" json:"mount,omitempty"`
+	Uts   bool `protobuf:"varint,3,opt,name=uts,proto3" json:"uts,omitempty"` // the hostname is the job id
+	Ipc   bool `protobuf:"varint,4,opt,name=ipc,proto3" json:"ipc,omitempty"`
+	Net   bool `protobuf:"varint,5,opt,name=net,proto3" json:"net,omitempty"` // only a loopback device
 }
 
-func (x *WorkerStartResponse) Reset() {
-	*x = WorkerStartResponse{}
+func (x *Namespaces) Reset() {
+	*x = Namespaces{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_jobworker_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -179,13 +526,13 @@ func (x *WorkerStartResponse) Reset() {
 	}
 }
 
-func (x *WorkerStartResponse) String() string {
+func (x *Namespaces) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WorkerStartResponse) ProtoMessage() {}
+func (*Namespaces) ProtoMessage() {}
 
-func (x *WorkerStartResponse) ProtoReflect() protoreflect.Message {
+func (x *Namespaces) ProtoReflect() protoreflect.Message {
 	mi := &file_jobworker_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -197,33 +544,58 @@ func (x *WorkerStartResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WorkerStartResponse.ProtoReflect.Descriptor instead.
-func (*WorkerStartResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Namespaces.ProtoReflect.Descriptor instead.
+func (*Namespaces) Descriptor() ([]byte, []int) {
 	return file_jobworker_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *WorkerStartResponse) GetJobId() string {
+func (x *Namespaces) GetPid() bool {
 	if x != nil {
-		return x.JobId
+		return x.Pid
 	}
-	return ""
+	return false
 }
 
-func (x *WorkerStartResponse) GetLog() string {
+func (x *Namespaces) GetMount() bool {
 	if x != nil {
-		return x.Log
+		return x.Mount
 	}
-	return ""
+	return false
 }
 
-type WorkerStopResponse struct {
+func (x *Namespaces) GetUts() bool {
+	if x != nil {
+		return x.Uts
+	}
+	return false
+}
+
+func (x *Namespaces) GetIpc() bool {
+	if x != nil {
+		return x.Ipc
+	}
+	return false
+}
+
+func (x *Namespaces) GetNet() bool {
+	if x != nil {
+		return x.Net
+	}
+	return false
+}
+
+// TerminalSize in characters
+type TerminalSize struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Rows uint32 `protobuf:"varint,1,opt,name=rows,proto3" json:"rows,omitempty"`
+	Cols uint32 `protobuf:"varint,2,opt,name=cols,proto3" json:"cols,omitempty"`
 }
 
-func (x *WorkerStopResponse) Reset() {
-	*x = WorkerStopResponse{}
+func (x *TerminalSize) Reset() {
+	*x = TerminalSize{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_jobworker_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -231,13 +603,13 @@ func (x *WorkerStopResponse) Reset() {
 	}
 }
 
-func (x *WorkerStopResponse) String() string {
+func (x *TerminalSize) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WorkerStopResponse) ProtoMessage() {}
+func (*TerminalSize) ProtoMessage() {}
 
-func (x *WorkerStopResponse) ProtoReflect() protoreflect.Message {
+func (x *TerminalSize) ProtoReflect() protoreflect.Message {
 	mi := &file_jobworker_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -249,22 +621,37 @@ func (x *WorkerStopResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WorkerStopResponse.ProtoReflect.Descriptor instead.
-func (*WorkerStopResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use TerminalSize.ProtoReflect.Descriptor instead.
+func (*TerminalSize) Descriptor() ([]byte, []int) {
 	return file_jobworker_proto_rawDescGZIP(), []int{4}
 }
 
-type WorkerQueryResponse struct {
+func (x *TerminalSize) GetRows() uint32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *TerminalSize) GetCols() uint32 {
+	if x != nil {
+		return x.Cols
+	}
+	return 0
+}
+
+type WorkerStopRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	JobId  string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	JobId          string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Signal         string `protobuf:"bytes,2,opt,name=signal,proto3" json:"signal,omitempty"`                                        // sent to every process of the job first, i.e. SIGINT, SIGTERM when empty
+	TimeoutSeconds int64  `protobuf:"varint,3,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"` // time to exit before everything is killed, server default when zero
 }
 
-func (x *WorkerQueryResponse) Reset() {
-	*x = WorkerQueryResponse{}
+func (x *WorkerStopRequest) Reset() {
+	*x = WorkerStopRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_jobworker_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -272,13 +659,13 @@ func (x *WorkerQueryResponse) Reset() {
 	}
 }
 
-func (x *WorkerQueryResponse) String() string {
+func (x *WorkerStopRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WorkerQueryResponse) ProtoMessage() {}
+func (*WorkerStopRequest) ProtoMessage() {}
 
-func (x *WorkerQueryResponse) ProtoReflect() protoreflect.Message {
+func (x *WorkerStopRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_jobworker_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -290,21 +677,1321 @@ func (x *WorkerQueryResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WorkerQueryResponse.ProtoReflect.Descriptor instead.
-func (*WorkerQueryResponse) Descriptor() ([]byte, []int) {
-	return file_jobworker_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use WorkerStopRequest.ProtoReflect.Descriptor instead.
+func (*WorkerStopRequest) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *WorkerStopRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *WorkerStopRequest) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *WorkerStopRequest) GetTimeoutSeconds() int64 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+// WorkerStdinRequest is a piece of a job's stdin, the first message names the job
+type WorkerStdinRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Data  []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Eof   bool   `protobuf:"varint,3,opt,name=eof,proto3" json:"eof,omitempty"` // close stdin after data, the stream can end without closing it
+}
+
+func (x *WorkerStdinRequest) Reset() {
+	*x = WorkerStdinRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerStdinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerStdinRequest) ProtoMessage() {}
+
+func (x *WorkerStdinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerStdinRequest.ProtoReflect.Descriptor instead.
+func (*WorkerStdinRequest) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WorkerStdinRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *WorkerStdinRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *WorkerStdinRequest) GetEof() bool {
+	if x != nil {
+		return x.Eof
+	}
+	return false
+}
+
+type WorkerStdinResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BytesWritten int64 `protobuf:"varint,1,opt,name=bytes_written,json=bytesWritten,proto3" json:"bytes_written,omitempty"`
+}
+
+func (x *WorkerStdinResponse) Reset() {
+	*x = WorkerStdinResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerStdinResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerStdinResponse) ProtoMessage() {}
+
+func (x *WorkerStdinResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerStdinResponse.ProtoReflect.Descriptor instead.
+func (*WorkerStdinResponse) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WorkerStdinResponse) GetBytesWritten() int64 {
+	if x != nil {
+		return x.BytesWritten
+	}
+	return 0
+}
+
+// WorkerAttachRequest carries keystrokes and window size changes to a job's terminal,
+// the first message names the job
+type WorkerAttachRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId  string        `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Data   []byte        `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`     // written to the terminal as typed
+	Resize *TerminalSize `protobuf:"bytes,3,opt,name=resize,proto3" json:"resize,omitempty"` // set when the client's window changed size
+}
+
+func (x *WorkerAttachRequest) Reset() {
+	*x = WorkerAttachRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerAttachRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerAttachRequest) ProtoMessage() {}
+
+func (x *WorkerAttachRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerAttachRequest.ProtoReflect.Descriptor instead.
+func (*WorkerAttachRequest) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WorkerAttachRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *WorkerAttachRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *WorkerAttachRequest) GetResize() *TerminalSize {
+	if x != nil {
+		return x.Resize
+	}
+	return nil
+}
+
+type WorkerAttachResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data   []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`      // raw terminal output
+	Offset int64  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"` // position of data in the job's output
+}
+
+func (x *WorkerAttachResponse) Reset() {
+	*x = WorkerAttachResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerAttachResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerAttachResponse) ProtoMessage() {}
+
+func (x *WorkerAttachResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerAttachResponse.ProtoReflect.Descriptor instead.
+func (*WorkerAttachResponse) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WorkerAttachResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *WorkerAttachResponse) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type WorkerSignalRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId  string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Signal string `protobuf:"bytes,2,opt,name=signal,proto3" json:"signal,omitempty"` // i.e. SIGHUP or HUP
+}
+
+func (x *WorkerSignalRequest) Reset() {
+	*x = WorkerSignalRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerSignalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerSignalRequest) ProtoMessage() {}
+
+func (x *WorkerSignalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerSignalRequest.ProtoReflect.Descriptor instead.
+func (*WorkerSignalRequest) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WorkerSignalRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *WorkerSignalRequest) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+type WorkerSignalResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WorkerSignalResponse) Reset() {
+	*x = WorkerSignalResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerSignalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerSignalResponse) ProtoMessage() {}
+
+func (x *WorkerSignalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerSignalResponse.ProtoReflect.Descriptor instead.
+func (*WorkerSignalResponse) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{11}
+}
+
+type WorkerPauseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+}
+
+func (x *WorkerPauseRequest) Reset() {
+	*x = WorkerPauseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerPauseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerPauseRequest) ProtoMessage() {}
+
+func (x *WorkerPauseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerPauseRequest.ProtoReflect.Descriptor instead.
+func (*WorkerPauseRequest) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WorkerPauseRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type WorkerPauseResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WorkerPauseResponse) Reset() {
+	*x = WorkerPauseResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerPauseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerPauseResponse) ProtoMessage() {}
+
+func (x *WorkerPauseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerPauseResponse.ProtoReflect.Descriptor instead.
+func (*WorkerPauseResponse) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{13}
+}
+
+type WorkerResumeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+}
+
+func (x *WorkerResumeRequest) Reset() {
+	*x = WorkerResumeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerResumeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerResumeRequest) ProtoMessage() {}
+
+func (x *WorkerResumeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerResumeRequest.ProtoReflect.Descriptor instead.
+func (*WorkerResumeRequest) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WorkerResumeRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type WorkerResumeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WorkerResumeResponse) Reset() {
+	*x = WorkerResumeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerResumeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerResumeResponse) ProtoMessage() {}
+
+func (x *WorkerResumeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerResumeResponse.ProtoReflect.Descriptor instead.
+func (*WorkerResumeResponse) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{15}
+}
+
+type WorkerQueryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+}
+
+func (x *WorkerQueryRequest) Reset() {
+	*x = WorkerQueryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerQueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerQueryRequest) ProtoMessage() {}
+
+func (x *WorkerQueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerQueryRequest.ProtoReflect.Descriptor instead.
+func (*WorkerQueryRequest) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *WorkerQueryRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type WorkerStartResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// Deprecated: Do not use.
+	Log    string                 `protobuf:"bytes,2,opt,name=log,proto3" json:"log,omitempty"` // replaced by data
+	Stream OutputStream           `protobuf:"varint,3,opt,name=stream,proto3,enum=main.OutputStream" json:"stream,omitempty"`
+	Time   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`      // when the job wrote the output
+	Data   []byte                 `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`      // raw output, not split on lines
+	Offset int64                  `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"` // position of data in the job's output
+}
+
+func (x *WorkerStartResponse) Reset() {
+	*x = WorkerStartResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerStartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerStartResponse) ProtoMessage() {}
+
+func (x *WorkerStartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerStartResponse.ProtoReflect.Descriptor instead.
+func (*WorkerStartResponse) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WorkerStartResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// Deprecated: Do not use.
+func (x *WorkerStartResponse) GetLog() string {
+	if x != nil {
+		return x.Log
+	}
+	return ""
+}
+
+func (x *WorkerStartResponse) GetStream() OutputStream {
+	if x != nil {
+		return x.Stream
+	}
+	return OutputStream_OUTPUT_STREAM_STDOUT
+}
+
+func (x *WorkerStartResponse) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *WorkerStartResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *WorkerStartResponse) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type WorkerLogsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId  string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Follow bool   `protobuf:"varint,2,opt,name=follow,proto3" json:"follow,omitempty"` // keep streaming live output until the job ends
+	Offset int64  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"` // byte offset in the output to start from
+	Tail   int32  `protobuf:"varint,4,opt,name=tail,proto3" json:"tail,omitempty"`     // start from the last lines instead of offset when set
+}
+
+func (x *WorkerLogsRequest) Reset() {
+	*x = WorkerLogsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerLogsRequest) ProtoMessage() {}
+
+func (x *WorkerLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerLogsRequest.ProtoReflect.Descriptor instead.
+func (*WorkerLogsRequest) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WorkerLogsRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *WorkerLogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+func (x *WorkerLogsRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *WorkerLogsRequest) GetTail() int32 {
+	if x != nil {
+		return x.Tail
+	}
+	return 0
+}
+
+type WorkerLogsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId  string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Stream OutputStream           `protobuf:"varint,2,opt,name=stream,proto3,enum=main.OutputStream" json:"stream,omitempty"`
+	Time   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	Data   []byte                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	Offset int64                  `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"` // position of data in the job's output
+}
+
+func (x *WorkerLogsResponse) Reset() {
+	*x = WorkerLogsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerLogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerLogsResponse) ProtoMessage() {}
+
+func (x *WorkerLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerLogsResponse.ProtoReflect.Descriptor instead.
+func (*WorkerLogsResponse) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WorkerLogsResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *WorkerLogsResponse) GetStream() OutputStream {
+	if x != nil {
+		return x.Stream
+	}
+	return OutputStream_OUTPUT_STREAM_STDOUT
+}
+
+func (x *WorkerLogsResponse) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *WorkerLogsResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *WorkerLogsResponse) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type WorkerSubmitResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId string   `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	State JobState `protobuf:"varint,2,opt,name=state,proto3,enum=main.JobState" json:"state,omitempty"` // state when the response was sent
+}
+
+func (x *WorkerSubmitResponse) Reset() {
+	*x = WorkerSubmitResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerSubmitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerSubmitResponse) ProtoMessage() {}
+
+func (x *WorkerSubmitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerSubmitResponse.ProtoReflect.Descriptor instead.
+func (*WorkerSubmitResponse) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *WorkerSubmitResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *WorkerSubmitResponse) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+type WorkerStopResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WorkerStopResponse) Reset() {
+	*x = WorkerStopResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerStopResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerStopResponse) ProtoMessage() {}
+
+func (x *WorkerStopResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerStopResponse.ProtoReflect.Descriptor instead.
+func (*WorkerStopResponse) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{21}
+}
+
+type StateChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State JobState               `protobuf:"varint,1,opt,name=state,proto3,enum=main.JobState" json:"state,omitempty"`
+	Time  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+}
+
+func (x *StateChange) Reset() {
+	*x = StateChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StateChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateChange) ProtoMessage() {}
+
+func (x *StateChange) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateChange.ProtoReflect.Descriptor instead.
+func (*StateChange) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *StateChange) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+func (x *StateChange) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+type WorkerQueryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId     string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status    string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`                      // name of the state, i.e. running
+	ExitCode  int32                  `protobuf:"varint,3,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"` // -1 while running or when killed by a signal
+	Signal    string                 `protobuf:"bytes,4,opt,name=signal,proto3" json:"signal,omitempty"`
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Reason    TerminationReason      `protobuf:"varint,7,opt,name=reason,proto3,enum=main.TerminationReason" json:"reason,omitempty"`
+	State     JobState               `protobuf:"varint,8,opt,name=state,proto3,enum=main.JobState" json:"state,omitempty"`
+	History   []*StateChange         `protobuf:"bytes,9,rep,name=history,proto3" json:"history,omitempty"` // oldest first
+}
+
+func (x *WorkerQueryResponse) Reset() {
+	*x = WorkerQueryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerQueryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerQueryResponse) ProtoMessage() {}
+
+func (x *WorkerQueryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerQueryResponse.ProtoReflect.Descriptor instead.
+func (*WorkerQueryResponse) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *WorkerQueryResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *WorkerQueryResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WorkerQueryResponse) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *WorkerQueryResponse) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *WorkerQueryResponse) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *WorkerQueryResponse) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *WorkerQueryResponse) GetReason() TerminationReason {
+	if x != nil {
+		return x.Reason
+	}
+	return TerminationReason_TERMINATION_REASON_NONE
+}
+
+func (x *WorkerQueryResponse) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+func (x *WorkerQueryResponse) GetHistory() []*StateChange {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+// WorkerListRequest filters the caller's jobs, unset fields match every job
+type WorkerListRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	States    []JobState             `protobuf:"varint,1,rep,packed,name=states,proto3,enum=main.JobState" json:"states,omitempty"`
+	Command   string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`                      // substring of the command line
+	Since     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`                          // created at or after
+	Until     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=until,proto3" json:"until,omitempty"`                          // created before
+	PageSize  int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // zero for the server default
+	PageToken string                 `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page
+	AllUsers  bool                   `protobuf:"varint,7,opt,name=all_users,json=allUsers,proto3" json:"all_users,omitempty"`   // every user's jobs instead of the caller's, admins and auditors only
+}
+
+func (x *WorkerListRequest) Reset() {
+	*x = WorkerListRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerListRequest) ProtoMessage() {}
+
+func (x *WorkerListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerListRequest.ProtoReflect.Descriptor instead.
+func (*WorkerListRequest) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *WorkerListRequest) GetStates() []JobState {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *WorkerListRequest) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *WorkerListRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *WorkerListRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *WorkerListRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *WorkerListRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *WorkerListRequest) GetAllUsers() bool {
+	if x != nil {
+		return x.AllUsers
+	}
+	return false
+}
+
+type JobSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId      string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Command    []string               `protobuf:"bytes,2,rep,name=command,proto3" json:"command,omitempty"`
+	State      JobState               `protobuf:"varint,3,opt,name=state,proto3,enum=main.JobState" json:"state,omitempty"`
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	StartTime  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	ExitCode   int32                  `protobuf:"varint,7,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Username   string                 `protobuf:"bytes,8,opt,name=username,proto3" json:"username,omitempty"` // owner of the job
+}
+
+func (x *JobSummary) Reset() {
+	*x = JobSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobSummary) ProtoMessage() {}
+
+func (x *JobSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobSummary.ProtoReflect.Descriptor instead.
+func (*JobSummary) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *JobSummary) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobSummary) GetCommand() []string {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *JobSummary) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+func (x *JobSummary) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *JobSummary) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *JobSummary) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *JobSummary) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *JobSummary) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type WorkerListResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Jobs          []*JobSummary `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`                                          // oldest first
+	NextPageToken string        `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty on the last page
+}
+
+func (x *WorkerListResponse) Reset() {
+	*x = WorkerListResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jobworker_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkerListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerListResponse) ProtoMessage() {}
+
+func (x *WorkerListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jobworker_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerListResponse.ProtoReflect.Descriptor instead.
+func (*WorkerListResponse) Descriptor() ([]byte, []int) {
+	return file_jobworker_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *WorkerQueryResponse) GetJobId() string {
+func (x *WorkerListResponse) GetJobs() []*JobSummary {
 	if x != nil {
-		return x.JobId
+		return x.Jobs
 	}
-	return ""
+	return nil
 }
 
-func (x *WorkerQueryResponse) GetStatus() string {
+func (x *WorkerListResponse) GetNextPageToken() string {
 	if x != nil {
-		return x.Status
+		return x.NextPageToken
 	}
 	return ""
 }
@@ -313,40 +2000,308 @@ var File_jobworker_proto protoreflect.FileDescriptor
 
 var file_jobworker_proto_rawDesc = []byte{
 	0x0a, 0x0f, 0x6a, 0x6f, 0x62, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x12, 0x04, 0x6d, 0x61, 0x69, 0x6e, 0x22, 0x2e, 0x0a, 0x12, 0x57, 0x6f, 0x72, 0x6b, 0x65,
-	0x72, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a,
-	0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
-	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x22, 0x2a, 0x0a, 0x11, 0x57, 0x6f, 0x72, 0x6b, 0x65,
-	0x72, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06,
-	0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f,
-	0x62, 0x49, 0x64, 0x22, 0x2b, 0x0a, 0x12, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x51, 0x75, 0x65,
-	0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62,
+	0x6f, 0x12, 0x04, 0x6d, 0x61, 0x69, 0x6e, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x95, 0x01, 0x0a, 0x07, 0x49, 0x4f, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x19, 0x0a, 0x08,
+	0x72, 0x65, 0x61, 0x64, 0x5f, 0x62, 0x70, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x72, 0x65, 0x61, 0x64, 0x42, 0x70, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x77, 0x72, 0x69, 0x74, 0x65,
+	0x5f, 0x62, 0x70, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x77, 0x72, 0x69, 0x74,
+	0x65, 0x42, 0x70, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x69, 0x6f, 0x70,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6f, 0x70,
+	0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x69, 0x6f, 0x70, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x77, 0x72, 0x69, 0x74, 0x65, 0x49, 0x6f, 0x70, 0x73,
+	0x22, 0xa9, 0x01, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x70, 0x75, 0x5f, 0x71, 0x75, 0x6f, 0x74, 0x61,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x63, 0x70, 0x75, 0x51, 0x75, 0x6f, 0x74, 0x61,
+	0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x70, 0x75, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x70, 0x75, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12,
+	0x21, 0x0a, 0x0c, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x12, 0x1d, 0x0a, 0x02, 0x69, 0x6f, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x49, 0x4f, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x02, 0x69,
+	0x6f, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x69, 0x64, 0x73, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x07, 0x70, 0x69, 0x64, 0x73, 0x4d, 0x61, 0x78, 0x22, 0xa1, 0x03, 0x0a,
+	0x12, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x2c, 0x0a,
+	0x06, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x73, 0x52, 0x06, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x74,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x05, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x74,
+	0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x74, 0x74, 0x79, 0x12, 0x37, 0x0a, 0x0d,
+	0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x54, 0x65, 0x72, 0x6d, 0x69,
+	0x6e, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x52, 0x0c, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61,
+	0x6c, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x76, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x76, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x65, 0x61, 0x6e,
+	0x5f, 0x65, 0x6e, 0x76, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x63, 0x6c, 0x65, 0x61,
+	0x6e, 0x45, 0x6e, 0x76, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x77, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x63, 0x77, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6d, 0x61, 0x73, 0x6b, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x75, 0x6d, 0x61, 0x73, 0x6b, 0x12, 0x30, 0x0a, 0x0a,
+	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x73, 0x52, 0x0a, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x6f, 0x6f, 0x74, 0x66, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x72, 0x6f, 0x6f, 0x74, 0x66, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x63, 0x63, 0x6f, 0x6d,
+	0x70, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x63, 0x63, 0x6f, 0x6d, 0x70,
+	0x22, 0x6a, 0x0a, 0x0a, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x10,
+	0x0a, 0x03, 0x70, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x70, 0x69, 0x64,
+	0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x05, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x74, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x03, 0x75, 0x74, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x70, 0x63, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x69, 0x70, 0x63, 0x12, 0x10, 0x0a, 0x03, 0x6e, 0x65,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x6e, 0x65, 0x74, 0x22, 0x36, 0x0a, 0x0c,
+	0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x72, 0x6f, 0x77, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04,
+	0x63, 0x6f, 0x6c, 0x73, 0x22, 0x6b, 0x0a, 0x11, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74,
+	0x6f, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0e, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64,
+	0x73, 0x22, 0x51, 0x0a, 0x12, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x64, 0x69, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6f, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x03, 0x65, 0x6f, 0x66, 0x22, 0x3a, 0x0a, 0x13, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74,
+	0x64, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x62,
+	0x79, 0x74, 0x65, 0x73, 0x5f, 0x77, 0x72, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0c, 0x62, 0x79, 0x74, 0x65, 0x73, 0x57, 0x72, 0x69, 0x74, 0x74, 0x65, 0x6e,
+	0x22, 0x6c, 0x0a, 0x13, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x12, 0x2a, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e,
+	0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x52, 0x06, 0x72, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x42,
+	0x0a, 0x14, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66,
+	0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x22, 0x44, 0x0a, 0x13, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e,
+	0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x22, 0x16, 0x0a, 0x14, 0x57, 0x6f, 0x72, 0x6b,
+	0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x2b, 0x0a, 0x12, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x50, 0x61, 0x75, 0x73, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x22, 0x15, 0x0a,
+	0x13, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x50, 0x61, 0x75, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2c, 0x0a, 0x13, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65,
+	0x73, 0x75, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6a,
+	0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62,
+	0x49, 0x64, 0x22, 0x16, 0x0a, 0x14, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x73, 0x75,
+	0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2b, 0x0a, 0x12, 0x57, 0x6f,
+	0x72, 0x6b, 0x65, 0x72, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x22, 0xca, 0x01, 0x0a, 0x13, 0x57, 0x6f, 0x72, 0x6b,
+	0x65, 0x72, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x03, 0x6c, 0x6f, 0x67, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x02, 0x18, 0x01, 0x52, 0x03, 0x6c, 0x6f, 0x67, 0x12, 0x2a, 0x0a, 0x06,
+	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d,
+	0x61, 0x69, 0x6e, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x52, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06,
+	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66,
+	0x66, 0x73, 0x65, 0x74, 0x22, 0x6e, 0x0a, 0x11, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x4c, 0x6f,
+	0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62,
 	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64,
-	0x22, 0x3e, 0x0a, 0x13, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x12, 0x10,
-	0x0a, 0x03, 0x6c, 0x6f, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6c, 0x6f, 0x67,
-	0x22, 0x14, 0x0a, 0x12, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x44, 0x0a, 0x13, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72,
-	0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x15, 0x0a,
-	0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a,
-	0x6f, 0x62, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x32, 0xd0, 0x01, 0x0a,
-	0x06, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x3e, 0x0a, 0x07, 0x4a, 0x6f, 0x62, 0x53, 0x74,
-	0x6f, 0x70, 0x12, 0x17, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72,
-	0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x6d, 0x61,
-	0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x08, 0x4a, 0x6f, 0x62, 0x53, 0x74,
-	0x61, 0x72, 0x74, 0x12, 0x18, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65,
-	0x72, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e,
-	0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x61, 0x72, 0x74,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x41, 0x0a, 0x08,
-	0x4a, 0x6f, 0x62, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x18, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e,
-	0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72,
-	0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42,
-	0x10, 0x5a, 0x0e, 0x6a, 0x6f, 0x62, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x64, 0x61, 0x74,
-	0x61, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x74, 0x61, 0x69, 0x6c, 0x22, 0xb3, 0x01, 0x0a, 0x12, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x4c,
+	0x6f, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x6a,
+	0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62,
+	0x49, 0x64, 0x12, 0x2a, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x2e,
+	0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0x53, 0x0a, 0x14, 0x57, 0x6f,
+	0x72, 0x6b, 0x65, 0x72, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x12, 0x24, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e,
+	0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22,
+	0x14, 0x0a, 0x12, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x63, 0x0a, 0x0b, 0x53, 0x74, 0x61, 0x74, 0x65, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x12, 0x24, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x22, 0xef, 0x02, 0x0a, 0x13, 0x57,
+	0x6f, 0x72, 0x6b, 0x65, 0x72, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x78, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x35, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e,
+	0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e,
+	0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x2b, 0x0a, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x52, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x92, 0x02, 0x0a,
+	0x11, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65,
+	0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67,
+	0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x6c, 0x6c, 0x5f, 0x75, 0x73, 0x65, 0x72,
+	0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x6c, 0x6c, 0x55, 0x73, 0x65, 0x72,
+	0x73, 0x22, 0xcb, 0x02, 0x0a, 0x0a, 0x4a, 0x6f, 0x62, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79,
+	0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x12, 0x24, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0e, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x3b, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12,
+	0x35, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65,
+	0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x78, 0x69, 0x74, 0x5f, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x22,
+	0x62, 0x0a, 0x12, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x75,
+	0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e,
+	0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f,
+	0x6b, 0x65, 0x6e, 0x2a, 0x42, 0x0a, 0x0c, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x12, 0x18, 0x0a, 0x14, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f, 0x53, 0x54,
+	0x52, 0x45, 0x41, 0x4d, 0x5f, 0x53, 0x54, 0x44, 0x4f, 0x55, 0x54, 0x10, 0x00, 0x12, 0x18, 0x0a,
+	0x14, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f, 0x53, 0x54, 0x52, 0x45, 0x41, 0x4d, 0x5f, 0x53,
+	0x54, 0x44, 0x45, 0x52, 0x52, 0x10, 0x01, 0x2a, 0xf1, 0x01, 0x0a, 0x08, 0x4a, 0x6f, 0x62, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x15, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x15, 0x0a, 0x11, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x50, 0x45, 0x4e,
+	0x44, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x45, 0x5f, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x17, 0x0a,
+	0x13, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x55, 0x43, 0x43, 0x45,
+	0x45, 0x44, 0x45, 0x44, 0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x45, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x12, 0x16, 0x0a, 0x12,
+	0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x54, 0x4f, 0x50, 0x50, 0x49,
+	0x4e, 0x47, 0x10, 0x05, 0x12, 0x15, 0x0a, 0x11, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x45, 0x5f, 0x53, 0x54, 0x4f, 0x50, 0x50, 0x45, 0x44, 0x10, 0x06, 0x12, 0x14, 0x0a, 0x10, 0x4a,
+	0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x4b, 0x49, 0x4c, 0x4c, 0x45, 0x44, 0x10,
+	0x07, 0x12, 0x12, 0x0a, 0x0e, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x4c,
+	0x4f, 0x53, 0x54, 0x10, 0x08, 0x12, 0x14, 0x0a, 0x10, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x45, 0x5f, 0x50, 0x41, 0x55, 0x53, 0x45, 0x44, 0x10, 0x09, 0x2a, 0x97, 0x02, 0x0a, 0x11,
+	0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x12, 0x1b, 0x0a, 0x17, 0x54, 0x45, 0x52, 0x4d, 0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x52, 0x45, 0x41, 0x53, 0x4f, 0x4e, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x1d,
+	0x0a, 0x19, 0x54, 0x45, 0x52, 0x4d, 0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45,
+	0x41, 0x53, 0x4f, 0x4e, 0x5f, 0x45, 0x58, 0x49, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x1f, 0x0a,
+	0x1b, 0x54, 0x45, 0x52, 0x4d, 0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x41,
+	0x53, 0x4f, 0x4e, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x4c, 0x45, 0x44, 0x10, 0x02, 0x12, 0x1e,
+	0x0a, 0x1a, 0x54, 0x45, 0x52, 0x4d, 0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45,
+	0x41, 0x53, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x4f, 0x50, 0x50, 0x45, 0x44, 0x10, 0x03, 0x12, 0x21,
+	0x0a, 0x1d, 0x54, 0x45, 0x52, 0x4d, 0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45,
+	0x41, 0x53, 0x4f, 0x4e, 0x5f, 0x4f, 0x4f, 0x4d, 0x5f, 0x4b, 0x49, 0x4c, 0x4c, 0x45, 0x44, 0x10,
+	0x04, 0x12, 0x20, 0x0a, 0x1c, 0x54, 0x45, 0x52, 0x4d, 0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x52, 0x45, 0x41, 0x53, 0x4f, 0x4e, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x44, 0x5f, 0x4f, 0x55,
+	0x54, 0x10, 0x05, 0x12, 0x23, 0x0a, 0x1f, 0x54, 0x45, 0x52, 0x4d, 0x49, 0x4e, 0x41, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x41, 0x53, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x52, 0x54, 0x5f,
+	0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x06, 0x12, 0x1b, 0x0a, 0x17, 0x54, 0x45, 0x52, 0x4d,
+	0x49, 0x4e, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x41, 0x53, 0x4f, 0x4e, 0x5f, 0x4c,
+	0x4f, 0x53, 0x54, 0x10, 0x07, 0x32, 0xf5, 0x05, 0x0a, 0x06, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72,
+	0x12, 0x3e, 0x0a, 0x07, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x17, 0x2e, 0x6d, 0x61,
+	0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b,
+	0x65, 0x72, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x43, 0x0a, 0x08, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x18, 0x2e, 0x6d,
+	0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f,
+	0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x43, 0x0a, 0x09, 0x4a, 0x6f, 0x62, 0x53, 0x75, 0x62, 0x6d,
+	0x69, 0x74, 0x12, 0x18, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6d,
+	0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x41, 0x0a, 0x08, 0x4a, 0x6f,
+	0x62, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x18, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f,
+	0x72, 0x6b, 0x65, 0x72, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x19, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a,
+	0x07, 0x4a, 0x6f, 0x62, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x17, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e,
+	0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x18, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x4c,
+	0x6f, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12,
+	0x3e, 0x0a, 0x07, 0x4a, 0x6f, 0x62, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x17, 0x2e, 0x6d, 0x61, 0x69,
+	0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65,
+	0x72, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x43, 0x0a, 0x08, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x64, 0x69, 0x6e, 0x12, 0x18, 0x2e, 0x6d, 0x61,
+	0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x74, 0x64, 0x69, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72,
+	0x6b, 0x65, 0x72, 0x53, 0x74, 0x64, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x28, 0x01, 0x12, 0x48, 0x0a, 0x09, 0x4a, 0x6f, 0x62, 0x41, 0x74, 0x74, 0x61, 0x63,
+	0x68, 0x12, 0x19, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x41,
+	0x74, 0x74, 0x61, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6d,
+	0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x44,
+	0x0a, 0x09, 0x4a, 0x6f, 0x62, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x12, 0x19, 0x2e, 0x6d, 0x61,
+	0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f,
+	0x72, 0x6b, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x41, 0x0a, 0x08, 0x4a, 0x6f, 0x62, 0x50, 0x61, 0x75, 0x73, 0x65,
+	0x12, 0x18, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x50, 0x61,
+	0x75, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6d, 0x61, 0x69,
+	0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x50, 0x61, 0x75, 0x73, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x44, 0x0a, 0x09, 0x4a, 0x6f, 0x62, 0x52, 0x65,
+	0x73, 0x75, 0x6d, 0x65, 0x12, 0x19, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b,
+	0x65, 0x72, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1a, 0x2e, 0x6d, 0x61, 0x69, 0x6e, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x73,
+	0x75, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x10, 0x5a,
+	0x0e, 0x6a, 0x6f, 0x62, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x64, 0x61, 0x74, 0x61, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -361,27 +2316,94 @@ func file_jobworker_proto_rawDescGZIP() []byte {
 	return file_jobworker_proto_rawDescData
 }
 
-var file_jobworker_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_jobworker_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_jobworker_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_jobworker_proto_goTypes = []interface{}{
-	(*WorkerStartRequest)(nil),  // 0: main.WorkerStartRequest
-	(*WorkerStopRequest)(nil),   // 1: main.WorkerStopRequest
-	(*WorkerQueryRequest)(nil),  // 2: main.WorkerQueryRequest
-	(*WorkerStartResponse)(nil), // 3: main.WorkerStartResponse
-	(*WorkerStopResponse)(nil),  // 4: main.WorkerStopResponse
-	(*WorkerQueryResponse)(nil), // 5: main.WorkerQueryResponse
+	(OutputStream)(0),             // 0: main.OutputStream
+	(JobState)(0),                 // 1: main.JobState
+	(TerminationReason)(0),        // 2: main.TerminationReason
+	(*IOLimit)(nil),               // 3: main.IOLimit
+	(*ResourceLimits)(nil),        // 4: main.ResourceLimits
+	(*WorkerStartRequest)(nil),    // 5: main.WorkerStartRequest
+	(*Namespaces)(nil),            // 6: main.Namespaces
+	(*TerminalSize)(nil),          // 7: main.TerminalSize
+	(*WorkerStopRequest)(nil),     // 8: main.WorkerStopRequest
+	(*WorkerStdinRequest)(nil),    // 9: main.WorkerStdinRequest
+	(*WorkerStdinResponse)(nil),   // 10: main.WorkerStdinResponse
+	(*WorkerAttachRequest)(nil),   // 11: main.WorkerAttachRequest
+	(*WorkerAttachResponse)(nil),  // 12: main.WorkerAttachResponse
+	(*WorkerSignalRequest)(nil),   // 13: main.WorkerSignalRequest
+	(*WorkerSignalResponse)(nil),  // 14: main.WorkerSignalResponse
+	(*WorkerPauseRequest)(nil),    // 15: main.WorkerPauseRequest
+	(*WorkerPauseResponse)(nil),   // 16: main.WorkerPauseResponse
+	(*WorkerResumeRequest)(nil),   // 17: main.WorkerResumeRequest
+	(*WorkerResumeResponse)(nil),  // 18: main.WorkerResumeResponse
+	(*WorkerQueryRequest)(nil),    // 19: main.WorkerQueryRequest
+	(*WorkerStartResponse)(nil),   // 20: main.WorkerStartResponse
+	(*WorkerLogsRequest)(nil),     // 21: main.WorkerLogsRequest
+	(*WorkerLogsResponse)(nil),    // 22: main.WorkerLogsResponse
+	(*WorkerSubmitResponse)(nil),  // 23: main.WorkerSubmitResponse
+	(*WorkerStopResponse)(nil),    // 24: main.WorkerStopResponse
+	(*StateChange)(nil),           // 25: main.StateChange
+	(*WorkerQueryResponse)(nil),   // 26: main.WorkerQueryResponse
+	(*WorkerListRequest)(nil),     // 27: main.WorkerListRequest
+	(*JobSummary)(nil),            // 28: main.JobSummary
+	(*WorkerListResponse)(nil),    // 29: main.WorkerListResponse
+	(*timestamppb.Timestamp)(nil), // 30: google.protobuf.Timestamp
 }
 var file_jobworker_proto_depIdxs = []int32{
-	1, // 0: main.Worker.JobStop:input_type -> main.WorkerStopRequest
-	0, // 1: main.Worker.JobStart:input_type -> main.WorkerStartRequest
-	2, // 2: main.Worker.JobQuery:input_type -> main.WorkerQueryRequest
-	4, // 3: main.Worker.JobStop:output_type -> main.WorkerStopResponse
-	3, // 4: main.Worker.JobStart:output_type -> main.WorkerStartResponse
-	5, // 5: main.Worker.JobQuery:output_type -> main.WorkerQueryResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	3,  // 0: main.ResourceLimits.io:type_name -> main.IOLimit
+	4,  // 1: main.WorkerStartRequest.limits:type_name -> main.ResourceLimits
+	7,  // 2: main.WorkerStartRequest.terminal_size:type_name -> main.TerminalSize
+	6,  // 3: main.WorkerStartRequest.namespaces:type_name -> main.Namespaces
+	7,  // 4: main.WorkerAttachRequest.resize:type_name -> main.TerminalSize
+	0,  // 5: main.WorkerStartResponse.stream:type_name -> main.OutputStream
+	30, // 6: main.WorkerStartResponse.time:type_name -> google.protobuf.Timestamp
+	0,  // 7: main.WorkerLogsResponse.stream:type_name -> main.OutputStream
+	30, // 8: main.WorkerLogsResponse.time:type_name -> google.protobuf.Timestamp
+	1,  // 9: main.WorkerSubmitResponse.state:type_name -> main.JobState
+	1,  // 10: main.StateChange.state:type_name -> main.JobState
+	30, // 11: main.StateChange.time:type_name -> google.protobuf.Timestamp
+	30, // 12: main.WorkerQueryResponse.start_time:type_name -> google.protobuf.Timestamp
+	30, // 13: main.WorkerQueryResponse.end_time:type_name -> google.protobuf.Timestamp
+	2,  // 14: main.WorkerQueryResponse.reason:type_name -> main.TerminationReason
+	1,  // 15: main.WorkerQueryResponse.state:type_name -> main.JobState
+	25, // 16: main.WorkerQueryResponse.history:type_name -> main.StateChange
+	1,  // 17: main.WorkerListRequest.states:type_name -> main.JobState
+	30, // 18: main.WorkerListRequest.since:type_name -> google.protobuf.Timestamp
+	30, // 19: main.WorkerListRequest.until:type_name -> google.protobuf.Timestamp
+	1,  // 20: main.JobSummary.state:type_name -> main.JobState
+	30, // 21: main.JobSummary.create_time:type_name -> google.protobuf.Timestamp
+	30, // 22: main.JobSummary.start_time:type_name -> google.protobuf.Timestamp
+	30, // 23: main.JobSummary.end_time:type_name -> google.protobuf.Timestamp
+	28, // 24: main.WorkerListResponse.jobs:type_name -> main.JobSummary
+	8,  // 25: main.Worker.JobStop:input_type -> main.WorkerStopRequest
+	5,  // 26: main.Worker.JobStart:input_type -> main.WorkerStartRequest
+	5,  // 27: main.Worker.JobSubmit:input_type -> main.WorkerStartRequest
+	19, // 28: main.Worker.JobQuery:input_type -> main.WorkerQueryRequest
+	21, // 29: main.Worker.JobLogs:input_type -> main.WorkerLogsRequest
+	27, // 30: main.Worker.JobList:input_type -> main.WorkerListRequest
+	9,  // 31: main.Worker.JobStdin:input_type -> main.WorkerStdinRequest
+	11, // 32: main.Worker.JobAttach:input_type -> main.WorkerAttachRequest
+	13, // 33: main.Worker.JobSignal:input_type -> main.WorkerSignalRequest
+	15, // 34: main.Worker.JobPause:input_type -> main.WorkerPauseRequest
+	17, // 35: main.Worker.JobResume:input_type -> main.WorkerResumeRequest
+	24, // 36: main.Worker.JobStop:output_type -> main.WorkerStopResponse
+	20, // 37: main.Worker.JobStart:output_type -> main.WorkerStartResponse
+	23, // 38: main.Worker.JobSubmit:output_type -> main.WorkerSubmitResponse
+	26, // 39: main.Worker.JobQuery:output_type -> main.WorkerQueryResponse
+	22, // 40: main.Worker.JobLogs:output_type -> main.WorkerLogsResponse
+	29, // 41: main.Worker.JobList:output_type -> main.WorkerListResponse
+	10, // 42: main.Worker.JobStdin:output_type -> main.WorkerStdinResponse
+	12, // 43: main.Worker.JobAttach:output_type -> main.WorkerAttachResponse
+	14, // 44: main.Worker.JobSignal:output_type -> main.WorkerSignalResponse
+	16, // 45: main.Worker.JobPause:output_type -> main.WorkerPauseResponse
+	18, // 46: main.Worker.JobResume:output_type -> main.WorkerResumeResponse
+	36, // [36:47] is the sub-list for method output_type
+	25, // [25:36] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_jobworker_proto_init() }
@@ -391,7 +2413,7 @@ func file_jobworker_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_jobworker_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WorkerStartRequest); i {
+			switch v := v.(*IOLimit); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -403,7 +2425,7 @@ func file_jobworker_proto_init() {
 			}
 		}
 		file_jobworker_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WorkerStopRequest); i {
+			switch v := v.(*ResourceLimits); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -415,7 +2437,7 @@ func file_jobworker_proto_init() {
 			}
 		}
 		file_jobworker_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WorkerQueryRequest); i {
+			switch v := v.(*WorkerStartRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -427,7 +2449,7 @@ func file_jobworker_proto_init() {
 			}
 		}
 		file_jobworker_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WorkerStartResponse); i {
+			switch v := v.(*Namespaces); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -439,7 +2461,7 @@ func file_jobworker_proto_init() {
 			}
 		}
 		file_jobworker_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WorkerStopResponse); i {
+			switch v := v.(*TerminalSize); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -451,6 +2473,222 @@ func file_jobworker_proto_init() {
 			}
 		}
 		file_jobworker_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerStopRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerStdinRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerStdinResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerAttachRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerAttachResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerSignalRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerSignalResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerPauseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerPauseResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerResumeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerResumeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerQueryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerStartResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerLogsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerLogsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerSubmitResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerStopResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StateChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*WorkerQueryResponse); i {
 			case 0:
 				return &v.state
@@ -462,19 +2700,56 @@ func file_jobworker_proto_init() {
 				return nil
 			}
 		}
+		file_jobworker_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerListRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jobworker_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkerListResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_jobworker_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      3,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_jobworker_proto_goTypes,
 		DependencyIndexes: file_jobworker_proto_depIdxs,
+		EnumInfos:         file_jobworker_proto_enumTypes,
 		MessageInfos:      file_jobworker_proto_msgTypes,
 	}.Build()
 	File_jobworker_proto = out.File
@@ -24,7 +24,20 @@ const _ = grpc.SupportPackageIsVersion7
 type WorkerClient interface {
 	JobStop(ctx context.Context, in *WorkerStopRequest, opts ...grpc.CallOption) (*WorkerStopResponse, error)
 	JobStart(ctx context.Context, in *WorkerStartRequest, opts ...grpc.CallOption) (Worker_JobStartClient, error)
+	// JobSubmit starts a job without waiting for its output
+	JobSubmit(ctx context.Context, in *WorkerStartRequest, opts ...grpc.CallOption) (*WorkerSubmitResponse, error)
 	JobQuery(ctx context.Context, in *WorkerQueryRequest, opts ...grpc.CallOption) (*WorkerQueryResponse, error)
+	JobLogs(ctx context.Context, in *WorkerLogsRequest, opts ...grpc.CallOption) (Worker_JobLogsClient, error)
+	JobList(ctx context.Context, in *WorkerListRequest, opts ...grpc.CallOption) (*WorkerListResponse, error)
+	// JobStdin writes to the stdin of a job started with stdin
+	JobStdin(ctx context.Context, opts ...grpc.CallOption) (Worker_JobStdinClient, error)
+	// JobAttach connects to the terminal of a job started with tty
+	JobAttach(ctx context.Context, opts ...grpc.CallOption) (Worker_JobAttachClient, error)
+	// JobSignal sends a signal to every process of a running job
+	JobSignal(ctx context.Context, in *WorkerSignalRequest, opts ...grpc.CallOption) (*WorkerSignalResponse, error)
+	// JobPause freezes a running job through its cgroup until JobResume
+	JobPause(ctx context.Context, in *WorkerPauseRequest, opts ...grpc.CallOption) (*WorkerPauseResponse, error)
+	JobResume(ctx context.Context, in *WorkerResumeRequest, opts ...grpc.CallOption) (*WorkerResumeResponse, error)
 }
 
 type workerClient struct {
@@ -76,6 +89,15 @@ func (x *workerJobStartClient) Recv() (*WorkerStartResponse, error) {
 	return m, nil
 }
 
+func (c *workerClient) JobSubmit(ctx context.Context, in *WorkerStartRequest, opts ...grpc.CallOption) (*WorkerSubmitResponse, error) {
+	out := new(WorkerSubmitResponse)
+	err := c.cc.Invoke(ctx, "/main.Worker/JobSubmit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *workerClient) JobQuery(ctx context.Context, in *WorkerQueryRequest, opts ...grpc.CallOption) (*WorkerQueryResponse, error) {
 	out := new(WorkerQueryResponse)
 	err := c.cc.Invoke(ctx, "/main.Worker/JobQuery", in, out, opts...)
@@ -85,13 +107,159 @@ func (c *workerClient) JobQuery(ctx context.Context, in *WorkerQueryRequest, opt
 	return out, nil
 }
 
+func (c *workerClient) JobLogs(ctx context.Context, in *WorkerLogsRequest, opts ...grpc.CallOption) (Worker_JobLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Worker_ServiceDesc.Streams[1], "/main.Worker/JobLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workerJobLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Worker_JobLogsClient interface {
+	Recv() (*WorkerLogsResponse, error)
+	grpc.ClientStream
+}
+
+type workerJobLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *workerJobLogsClient) Recv() (*WorkerLogsResponse, error) {
+	m := new(WorkerLogsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *workerClient) JobList(ctx context.Context, in *WorkerListRequest, opts ...grpc.CallOption) (*WorkerListResponse, error) {
+	out := new(WorkerListResponse)
+	err := c.cc.Invoke(ctx, "/main.Worker/JobList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) JobStdin(ctx context.Context, opts ...grpc.CallOption) (Worker_JobStdinClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Worker_ServiceDesc.Streams[2], "/main.Worker/JobStdin", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workerJobStdinClient{stream}
+	return x, nil
+}
+
+type Worker_JobStdinClient interface {
+	Send(*WorkerStdinRequest) error
+	CloseAndRecv() (*WorkerStdinResponse, error)
+	grpc.ClientStream
+}
+
+type workerJobStdinClient struct {
+	grpc.ClientStream
+}
+
+func (x *workerJobStdinClient) Send(m *WorkerStdinRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *workerJobStdinClient) CloseAndRecv() (*WorkerStdinResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(WorkerStdinResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *workerClient) JobAttach(ctx context.Context, opts ...grpc.CallOption) (Worker_JobAttachClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Worker_ServiceDesc.Streams[3], "/main.Worker/JobAttach", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workerJobAttachClient{stream}
+	return x, nil
+}
+
+type Worker_JobAttachClient interface {
+	Send(*WorkerAttachRequest) error
+	Recv() (*WorkerAttachResponse, error)
+	grpc.ClientStream
+}
+
+type workerJobAttachClient struct {
+	grpc.ClientStream
+}
+
+func (x *workerJobAttachClient) Send(m *WorkerAttachRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *workerJobAttachClient) Recv() (*WorkerAttachResponse, error) {
+	m := new(WorkerAttachResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *workerClient) JobSignal(ctx context.Context, in *WorkerSignalRequest, opts ...grpc.CallOption) (*WorkerSignalResponse, error) {
+	out := new(WorkerSignalResponse)
+	err := c.cc.Invoke(ctx, "/main.Worker/JobSignal", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) JobPause(ctx context.Context, in *WorkerPauseRequest, opts ...grpc.CallOption) (*WorkerPauseResponse, error) {
+	out := new(WorkerPauseResponse)
+	err := c.cc.Invoke(ctx, "/main.Worker/JobPause", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) JobResume(ctx context.Context, in *WorkerResumeRequest, opts ...grpc.CallOption) (*WorkerResumeResponse, error) {
+	out := new(WorkerResumeResponse)
+	err := c.cc.Invoke(ctx, "/main.Worker/JobResume", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkerServer is the server API for Worker service.
 // All implementations must embed UnimplementedWorkerServer
 // for forward compatibility
 type WorkerServer interface {
 	JobStop(context.Context, *WorkerStopRequest) (*WorkerStopResponse, error)
 	JobStart(*WorkerStartRequest, Worker_JobStartServer) error
+	// JobSubmit starts a job without waiting for its output
+	JobSubmit(context.Context, *WorkerStartRequest) (*WorkerSubmitResponse, error)
 	JobQuery(context.Context, *WorkerQueryRequest) (*WorkerQueryResponse, error)
+	JobLogs(*WorkerLogsRequest, Worker_JobLogsServer) error
+	JobList(context.Context, *WorkerListRequest) (*WorkerListResponse, error)
+	// JobStdin writes to the stdin of a job started with stdin
+	JobStdin(Worker_JobStdinServer) error
+	// JobAttach connects to the terminal of a job started with tty
+	JobAttach(Worker_JobAttachServer) error
+	// JobSignal sends a signal to every process of a running job
+	JobSignal(context.Context, *WorkerSignalRequest) (*WorkerSignalResponse, error)
+	// JobPause freezes a running job through its cgroup until JobResume
+	JobPause(context.Context, *WorkerPauseRequest) (*WorkerPauseResponse, error)
+	JobResume(context.Context, *WorkerResumeRequest) (*WorkerResumeResponse, error)
 	mustEmbedUnimplementedWorkerServer()
 }
 
@@ -105,9 +273,33 @@ func (UnimplementedWorkerServer) JobStop(context.Context, *WorkerStopRequest) (*
 func (UnimplementedWorkerServer) JobStart(*WorkerStartRequest, Worker_JobStartServer) error {
 	return status.Errorf(codes.Unimplemented, "method JobStart not implemented")
 }
+func (UnimplementedWorkerServer) JobSubmit(context.Context, *WorkerStartRequest) (*WorkerSubmitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JobSubmit not implemented")
+}
 func (UnimplementedWorkerServer) JobQuery(context.Context, *WorkerQueryRequest) (*WorkerQueryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method JobQuery not implemented")
 }
+func (UnimplementedWorkerServer) JobLogs(*WorkerLogsRequest, Worker_JobLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method JobLogs not implemented")
+}
+func (UnimplementedWorkerServer) JobList(context.Context, *WorkerListRequest) (*WorkerListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JobList not implemented")
+}
+func (UnimplementedWorkerServer) JobStdin(Worker_JobStdinServer) error {
+	return status.Errorf(codes.Unimplemented, "method JobStdin not implemented")
+}
+func (UnimplementedWorkerServer) JobAttach(Worker_JobAttachServer) error {
+	return status.Errorf(codes.Unimplemented, "method JobAttach not implemented")
+}
+func (UnimplementedWorkerServer) JobSignal(context.Context, *WorkerSignalRequest) (*WorkerSignalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JobSignal not implemented")
+}
+func (UnimplementedWorkerServer) JobPause(context.Context, *WorkerPauseRequest) (*WorkerPauseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JobPause not implemented")
+}
+func (UnimplementedWorkerServer) JobResume(context.Context, *WorkerResumeRequest) (*WorkerResumeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JobResume not implemented")
+}
 func (UnimplementedWorkerServer) mustEmbedUnimplementedWorkerServer() {}
 
 // UnsafeWorkerServer may be embedded to opt out of forward compatibility for this service.
@@ -160,6 +352,24 @@ func (x *workerJobStartServer) Send(m *WorkerStartResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Worker_JobSubmit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkerStartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).JobSubmit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/main.Worker/JobSubmit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).JobSubmit(ctx, req.(*WorkerStartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Worker_JobQuery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(WorkerQueryRequest)
 	if err := dec(in); err != nil {
@@ -178,6 +388,151 @@ func _Worker_JobQuery_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Worker_JobLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WorkerLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkerServer).JobLogs(m, &workerJobLogsServer{stream})
+}
+
+type Worker_JobLogsServer interface {
+	Send(*WorkerLogsResponse) error
+	grpc.ServerStream
+}
+
+type workerJobLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *workerJobLogsServer) Send(m *WorkerLogsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Worker_JobList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkerListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).JobList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/main.Worker/JobList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).JobList(ctx, req.(*WorkerListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_JobStdin_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WorkerServer).JobStdin(&workerJobStdinServer{stream})
+}
+
+type Worker_JobStdinServer interface {
+	SendAndClose(*WorkerStdinResponse) error
+	Recv() (*WorkerStdinRequest, error)
+	grpc.ServerStream
+}
+
+type workerJobStdinServer struct {
+	grpc.ServerStream
+}
+
+func (x *workerJobStdinServer) SendAndClose(m *WorkerStdinResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *workerJobStdinServer) Recv() (*WorkerStdinRequest, error) {
+	m := new(WorkerStdinRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Worker_JobAttach_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WorkerServer).JobAttach(&workerJobAttachServer{stream})
+}
+
+type Worker_JobAttachServer interface {
+	Send(*WorkerAttachResponse) error
+	Recv() (*WorkerAttachRequest, error)
+	grpc.ServerStream
+}
+
+type workerJobAttachServer struct {
+	grpc.ServerStream
+}
+
+func (x *workerJobAttachServer) Send(m *WorkerAttachResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *workerJobAttachServer) Recv() (*WorkerAttachRequest, error) {
+	m := new(WorkerAttachRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Worker_JobSignal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkerSignalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).JobSignal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/main.Worker/JobSignal",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).JobSignal(ctx, req.(*WorkerSignalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_JobPause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkerPauseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).JobPause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/main.Worker/JobPause",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).JobPause(ctx, req.(*WorkerPauseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_JobResume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkerResumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).JobResume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/main.Worker/JobResume",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).JobResume(ctx, req.(*WorkerResumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Worker_ServiceDesc is the grpc.ServiceDesc for Worker service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -189,10 +544,30 @@ var Worker_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "JobStop",
 			Handler:    _Worker_JobStop_Handler,
 		},
+		{
+			MethodName: "JobSubmit",
+			Handler:    _Worker_JobSubmit_Handler,
+		},
 		{
 			MethodName: "JobQuery",
 			Handler:    _Worker_JobQuery_Handler,
 		},
+		{
+			MethodName: "JobList",
+			Handler:    _Worker_JobList_Handler,
+		},
+		{
+			MethodName: "JobSignal",
+			Handler:    _Worker_JobSignal_Handler,
+		},
+		{
+			MethodName: "JobPause",
+			Handler:    _Worker_JobPause_Handler,
+		},
+		{
+			MethodName: "JobResume",
+			Handler:    _Worker_JobResume_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -200,6 +575,22 @@ var Worker_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Worker_JobStart_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "JobLogs",
+			Handler:       _Worker_JobLogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "JobStdin",
+			Handler:       _Worker_JobStdin_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "JobAttach",
+			Handler:       _Worker_JobAttach_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "jobworker.proto",
 }
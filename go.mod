@@ -3,11 +3,13 @@ module github.com/sbui-dev/jobworker
 go 1.12
 
 require (
-	github.com/alecthomas/kingpin/v2 v2.4.0 // indirect
+	github.com/alecthomas/kingpin/v2 v2.4.0
 	github.com/cncf/xds/go v0.0.0-20231109132714-523115ebc101 // indirect
-	github.com/google/uuid v1.4.0 // indirect
-	github.com/stretchr/testify v1.8.4 // indirect
+	github.com/google/uuid v1.4.0
+	github.com/stretchr/testify v1.8.4
 	golang.org/x/net v0.18.0 // indirect
+	golang.org/x/sys v0.14.0
+	golang.org/x/term v0.14.0
 	google.golang.org/genproto v0.0.0-20231106174013-bbf56f31fb17 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20231106174013-bbf56f31fb17 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20231106174013-bbf56f31fb17 // indirect
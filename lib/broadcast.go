@@ -0,0 +1,236 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// Broadcaster stores a job's output and replicates it to any number of subscribers.
+// Writes only append to the log, so a slow subscriber falls behind instead of
+// blocking the job
+type Broadcaster struct {
+	mutex  sync.Mutex
+	log    outputLog
+	closed bool
+	// notify is closed and replaced on every write and on close to wake up subscribers
+	notify chan struct{}
+}
+
+// NewBroadcaster returns a Broadcaster keeping all of the output in memory
+func NewBroadcaster() *Broadcaster {
+	return newBroadcaster(newMemoryLog(OutputOptions{}))
+}
+
+// NewOutput returns a Broadcaster for a new job, spooling the output to a file
+// when opts has a folder
+func NewOutput(jobID string, opts OutputOptions) (*Broadcaster, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.Dir == "" {
+		return newBroadcaster(newMemoryLog(opts)), nil
+	}
+	spool, err := newFileLog(jobID, opts)
+	if err != nil {
+		return nil, err
+	}
+	return newBroadcaster(spool), nil
+}
+
+// OpenOutput returns a closed Broadcaster with the output a job spooled before
+func OpenOutput(jobID string, opts OutputOptions) (*Broadcaster, error) {
+	b := NewBroadcaster()
+	if opts.Dir != "" {
+		spool, err := openFileLog(jobID, opts)
+		if err != nil {
+			return nil, err
+		}
+		b = newBroadcaster(spool)
+	}
+	b.Close()
+	return b, nil
+}
+
+func newBroadcaster(output outputLog) *Broadcaster {
+	return &Broadcaster{log: output, notify: make(chan struct{})}
+}
+
+// Write adds a chunk to the log and wakes up the subscribers.
+// The chunk's Offset is set to its position in the output
+func (b *Broadcaster) Write(chunk OutputChunk) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.closed {
+		return
+	}
+	chunk.Offset = b.log.end()
+	if err := b.log.append(chunk); err != nil {
+		log.Printf("%v", err)
+		return
+	}
+	b.wake()
+}
+
+// Close marks the end of the output, subscribers' channels are closed once
+// they have received every chunk
+func (b *Broadcaster) Close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	if err := b.log.close(); err != nil {
+		log.Printf("%v", err)
+	}
+	b.wake()
+}
+
+// Chunks returns the output kept so far
+func (b *Broadcaster) Chunks() []OutputChunk {
+	var chunks []OutputChunk
+	for chunk := range b.SubscribeFrom(context.Background(), 0, false) {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// Subscribe returns a channel with the output from the beginning followed by live
+// output. The channel is closed after the last chunk or when ctx is done
+func (b *Broadcaster) Subscribe(ctx context.Context) <-chan OutputChunk {
+	return b.SubscribeFrom(ctx, 0, true)
+}
+
+// SubscribeFrom returns a channel with the output starting at the byte offset.
+// Output dropped by rotation is skipped. Without follow the channel is closed
+// after the output written so far, otherwise live output is sent until the
+// output ends or ctx is done
+func (b *Broadcaster) SubscribeFrom(ctx context.Context, offset int64, follow bool) <-chan OutputChunk {
+	out := make(chan OutputChunk)
+	go func() {
+		defer close(out)
+		next := offset
+		for {
+			b.mutex.Lock()
+			if start := b.log.start(); next < start {
+				next = start
+			}
+			pending, err := b.log.read(next, readSize)
+			closed := b.closed
+			notify := b.notify
+			b.mutex.Unlock()
+			if err != nil {
+				log.Printf("%v", err)
+				return
+			}
+
+			for _, chunk := range pending {
+				select {
+				case out <- chunk:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if len(pending) > 0 {
+				last := pending[len(pending)-1]
+				next = last.Offset + int64(len(last.Data))
+				continue
+			}
+			if closed || !follow {
+				return
+			}
+
+			select {
+			case <-notify:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// TailOffset returns the byte offset where the last n lines of the output start.
+// The output is scanned backwards from its end, the mutex is only held while
+// a window of it is read so the job's writes are not held up by the scan
+func (b *Broadcaster) TailOffset(n int) int64 {
+	b.mutex.Lock()
+	start, end := b.log.start(), b.log.end()
+	b.mutex.Unlock()
+	if n <= 0 {
+		return end
+	}
+
+	// the output is only appended to, so what is between start and end does
+	// not change while it is scanned unless rotation drops it
+	lines := 0
+	for to := end; to > start; {
+		from := to - readSize
+		if from < start {
+			from = start
+		}
+		data, first, err := b.readWindow(from, to)
+		if err != nil {
+			log.Printf("%v", err)
+			return start
+		}
+		for i := len(data) - 1; i >= 0; i-- {
+			offset := first + int64(i)
+			// a trailing newline ends the last line instead of starting a new one
+			if data[i] != '\n' || offset == end-1 {
+				continue
+			}
+			if lines++; lines == n {
+				return offset + 1
+			}
+		}
+		if first > from {
+			// rotation dropped the rest, the output starts at first now
+			return first
+		}
+		to = from
+	}
+	return start
+}
+
+// readWindow() helper func to read the output between the offsets from and to,
+// returning where the data starts, which is after from when rotation dropped it
+func (b *Broadcaster) readWindow(from int64, to int64) ([]byte, int64, error) {
+	var data []byte
+	first := from
+	for next := from; next < to; {
+		b.mutex.Lock()
+		chunks, err := b.log.read(next, int(to-next))
+		b.mutex.Unlock()
+		if err != nil {
+			return nil, first, err
+		}
+		if len(chunks) == 0 {
+			break
+		}
+		for _, chunk := range chunks {
+			if chunk.Offset >= to {
+				return data, first, nil
+			}
+			if len(data) == 0 {
+				first = chunk.Offset
+			}
+			end := chunk.Offset + int64(len(chunk.Data))
+			if end > to {
+				chunk.Data = chunk.Data[:to-chunk.Offset]
+			}
+			data = append(data, chunk.Data...)
+			next = end
+		}
+	}
+	return data, first, nil
+}
+
+// wake() helper func to notify the subscribers, the caller must hold the mutex
+func (b *Broadcaster) wake() {
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
@@ -0,0 +1,141 @@
+package jobworker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readAll(sub <-chan OutputChunk) string {
+	var out []byte
+	for chunk := range sub {
+		out = append(out, chunk.Data...)
+	}
+	return string(out)
+}
+
+func TestBroadcasterHistoryAndLive(t *testing.T) {
+	b := NewBroadcaster()
+	b.Write(OutputChunk{Data: []byte("a")})
+
+	sub := b.Subscribe(context.Background())
+	chunk := <-sub
+	assert.Equal(t, "a", string(chunk.Data))
+
+	b.Write(OutputChunk{Data: []byte("b")})
+	chunk = <-sub
+	assert.Equal(t, "b", string(chunk.Data))
+
+	b.Write(OutputChunk{Data: []byte("c")})
+	b.Close()
+	assert.Equal(t, "c", readAll(sub))
+
+	// writes after close are dropped and late subscribers get everything
+	b.Write(OutputChunk{Data: []byte("d")})
+	assert.Equal(t, "abc", readAll(b.Subscribe(context.Background())))
+	assert.Equal(t, 3, len(b.Chunks()))
+}
+
+func TestBroadcasterSlowSubscriber(t *testing.T) {
+	b := NewBroadcaster()
+	slow := b.Subscribe(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 1000; i++ {
+			b.Write(OutputChunk{Data: []byte("x")})
+		}
+		b.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("writes were blocked by a subscriber that is not reading")
+	}
+	assert.Equal(t, 1000, len(readAll(slow)))
+}
+
+func TestBroadcasterUnsubscribe(t *testing.T) {
+	b := NewBroadcaster()
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := b.Subscribe(ctx)
+	b.Write(OutputChunk{Data: []byte("a")})
+	cancel()
+
+	// the channel is closed once the subscriber goes away, even though the output is still open
+	assert.Eventually(t, func() bool {
+		select {
+		case _, ok := <-sub:
+			return !ok
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestBroadcasterOffsets(t *testing.T) {
+	b := NewBroadcaster()
+	b.Write(OutputChunk{Data: []byte("abc")})
+	b.Write(OutputChunk{Data: []byte("def")})
+
+	chunks := b.Chunks()
+	assert.Equal(t, int64(0), chunks[0].Offset)
+	assert.Equal(t, int64(3), chunks[1].Offset)
+
+	// without follow only the output written so far is sent
+	assert.Equal(t, "abcdef", readAll(b.SubscribeFrom(context.Background(), 0, false)))
+	assert.Equal(t, "cdef", readAll(b.SubscribeFrom(context.Background(), 2, false)))
+	assert.Equal(t, "ef", readAll(b.SubscribeFrom(context.Background(), 4, false)))
+	assert.Equal(t, "", readAll(b.SubscribeFrom(context.Background(), 10, false)))
+
+	sub := b.SubscribeFrom(context.Background(), 4, true)
+	chunk := <-sub
+	assert.Equal(t, "ef", string(chunk.Data))
+	assert.Equal(t, int64(4), chunk.Offset)
+
+	b.Write(OutputChunk{Data: []byte("gh")})
+	b.Close()
+	assert.Equal(t, "gh", readAll(sub))
+}
+
+func TestBroadcasterTailOffset(t *testing.T) {
+	b := NewBroadcaster()
+	assert.Equal(t, int64(0), b.TailOffset(3))
+
+	b.Write(OutputChunk{Data: []byte("one\ntw")})
+	b.Write(OutputChunk{Data: []byte("o\nthree\n")})
+
+	tail := func(n int) string {
+		return readAll(b.SubscribeFrom(context.Background(), b.TailOffset(n), false))
+	}
+	assert.Equal(t, "three\n", tail(1))
+	assert.Equal(t, "two\nthree\n", tail(2))
+	assert.Equal(t, "one\ntwo\nthree\n", tail(3))
+	assert.Equal(t, "one\ntwo\nthree\n", tail(10))
+
+	// a partial last line counts as a line
+	b.Write(OutputChunk{Data: []byte("fo")})
+	assert.Equal(t, "fo", tail(1))
+	assert.Equal(t, "three\nfo", tail(2))
+
+	// lines further back than a window are found too
+	b = NewBroadcaster()
+	all := writeLines(b, 300)
+	assert.Equal(t, int64(len(all)-200*1024), b.TailOffset(200))
+	assert.Equal(t, int64(0), b.TailOffset(300))
+
+	// with rotation the tail cannot go back further than the output kept
+	b, err := NewOutput("job1", OutputOptions{MaxSize: 2 * ChunkSize})
+	assert.Nil(t, err)
+	all = writeLines(b, 300)
+	b.mutex.Lock()
+	start := b.log.start()
+	b.mutex.Unlock()
+	assert.True(t, start > 0, "expected rotation to drop output")
+	assert.Equal(t, start, b.TailOffset(300))
+	assert.Equal(t, int64(len(all)-10*1024), b.TailOffset(10))
+}
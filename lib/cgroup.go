@@ -4,21 +4,194 @@ package jobworker
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
 )
 
 const (
-	CPUMaxFile = "cpu.max"
-	MEMMaxFile = "mem.max"
-	IOMaxFile  = "io.max"
-	JobFolder  = "/sys/fs/cgroup/jobworker/"
-	MaxCPU     = 20000
-	MaxMEM     = 10000
-	MaxDiskIO  = 12800
+	CPUMaxFile         = "cpu.max"
+	MEMMaxFile         = "memory.max"
+	IOMaxFile          = "io.max"
+	PidsMaxFile        = "pids.max"
+	MEMEventsFile      = "memory.events"
+	SubtreeControlFile = "cgroup.subtree_control"
+	KillFile           = "cgroup.kill"
+	ProcsFile          = "cgroup.procs"
+	FreezeFile         = "cgroup.freeze"
+	JobFolder          = "/sys/fs/cgroup/jobworker/"
+	Controllers        = "+cpu +memory +io +pids"
+	MaxCPU             = 100000
+	CPUPeriod          = 200000
+	MaxMEM             = 134217728
+	BlockDevicesFolder = "/sys/dev/block"
 )
 
+// CGroup manages the cgroup v2 hierarchy jobs are placed in.
+// The layout is <root>/<username>/<jobID>/
+type CGroup struct {
+	root string
+	// fake skips the cgroup v2 mount check so tests can use a plain directory
+	fake bool
+}
+
+// NewCGroup creates a cgroup manager rooted at root, normally JobFolder.
+// root has to be inside a cgroup v2 mount
+func NewCGroup(root string) *CGroup {
+	return &CGroup{root: root}
+}
+
+// SetupCGroup creates the default jobworker cgroup
 func SetupCGroup() error {
-	if err := os.Mkdir(JobFolder, os.ModePerm); err != nil {
+	return NewCGroup(JobFolder).Setup()
+}
+
+// Setup creates the root folder and enables the controllers needed by the
+// job limits for it and its children
+func (c *CGroup) Setup() error {
+	parent := filepath.Dir(filepath.Clean(c.root))
+	if !c.fake {
+		// on a cgroup v1 or hybrid host the parent is a tmpfs, where the
+		// folders and files below would be created as plain ones
+		var fs unix.Statfs_t
+		if err := unix.Statfs(parent, &fs); err != nil {
+			return fmt.Errorf("failed to stat %s: %v", parent, err)
+		}
+		if fs.Type != unix.CGROUP2_SUPER_MAGIC {
+			return fmt.Errorf("%s is not a cgroup v2 mount", parent)
+		}
+	}
+	if err := os.MkdirAll(c.root, os.ModePerm); err != nil {
 		return fmt.Errorf("failed to create folder: %v", err)
 	}
+	if err := writeCGroupFile(parent, SubtreeControlFile, Controllers); err != nil {
+		return err
+	}
+	return writeCGroupFile(c.root, SubtreeControlFile, Controllers)
+}
+
+// CreateJobGroup creates the cgroup for a user's job with the resource
+// limits written and returns its path
+func (c *CGroup) CreateJobGroup(username string, jobID string, limits Limits) (string, error) {
+	if username == "" || jobID == "" {
+		return "", fmt.Errorf("username and job id are required to create a cgroup")
+	}
+
+	jobPath := c.JobGroupPath(username, jobID)
+	userPath := filepath.Dir(jobPath)
+	if err := os.MkdirAll(userPath, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create user cgroup: %v", err)
+	}
+	if err := writeCGroupFile(userPath, SubtreeControlFile, Controllers); err != nil {
+		return "", err
+	}
+
+	if err := os.Mkdir(jobPath, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create job cgroup: %v", err)
+	}
+
+	files := []struct {
+		file  string
+		value string
+	}{
+		{CPUMaxFile, limits.cpuMax()},
+		{MEMMaxFile, limitValue(limits.Memory)},
+		{PidsMaxFile, limitValue(limits.Pids)},
+	}
+	for _, f := range files {
+		if err := writeCGroupFile(jobPath, f.file, f.value); err != nil {
+			c.RemoveJobGroup(jobPath)
+			return "", err
+		}
+	}
+	if err := writeIOMax(jobPath, limits.ioMax()); err != nil {
+		c.RemoveJobGroup(jobPath)
+		return "", err
+	}
+
+	return jobPath, nil
+}
+
+// JobGroupPath returns the path of the cgroup of a user's job, whether it exists or not
+func (c *CGroup) JobGroupPath(username string, jobID string) string {
+	return filepath.Join(c.root, username, jobID)
+}
+
+// RemoveJobGroup removes a job cgroup once all of its processes have exited,
+// a cgroup that still has processes fails with EBUSY
+func (c *CGroup) RemoveJobGroup(path string) error {
+	// cgroupfs directories are removed with rmdir even though they list interface files
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// OOMKilled checks memory.events of a job cgroup for processes killed by the OOM killer
+func (c *CGroup) OOMKilled(path string) bool {
+	data, err := os.ReadFile(filepath.Join(path, MEMEventsFile))
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "oom_kill" {
+			continue
+		}
+		count, err := strconv.Atoi(fields[1])
+		return err == nil && count > 0
+	}
+	return false
+}
+
+// KillJobGroup kills every process in a job cgroup with SIGKILL through cgroup.kill
+func (c *CGroup) KillJobGroup(path string) error {
+	return writeCGroupFile(path, KillFile, "1")
+}
+
+// FreezeJobGroup freezes or thaws every process in a job cgroup through cgroup.freeze
+func (c *CGroup) FreezeJobGroup(path string, frozen bool) error {
+	value := "0"
+	if frozen {
+		value = "1"
+	}
+	return writeCGroupFile(path, FreezeFile, value)
+}
+
+// JobGroupEmpty checks cgroup.procs of a job cgroup for processes still running
+func (c *CGroup) JobGroupEmpty(path string) bool {
+	data, err := os.ReadFile(filepath.Join(path, ProcsFile))
+	if err != nil {
+		return true
+	}
+	return strings.TrimSpace(string(data)) == ""
+}
+
+// writeIOMax helper func to write io.max, the kernel only accepts one
+// device per write so each line is written separately
+func writeIOMax(dir string, lines []string) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	f, err := os.OpenFile(filepath.Join(dir, IOMaxFile), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", IOMaxFile, err)
+	}
+	defer f.Close()
+	for _, line := range lines {
+		if _, err := f.WriteString(line + "\n"); err != nil {
+			return fmt.Errorf("failed to write %s: %v", IOMaxFile, err)
+		}
+	}
+	return nil
+}
+
+// writeCGroupFile helper func to write a value to a cgroup interface file
+func writeCGroupFile(dir string, file string, value string) error {
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(value), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", file, err)
+	}
 	return nil
 }
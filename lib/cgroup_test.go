@@ -0,0 +1,151 @@
+package jobworker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readCGroupFile(t *testing.T, path string) string {
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err, "error reading cgroup file")
+	return string(data)
+}
+
+// fakeCGroup helper func to set up a cgroup manager on a plain directory standing in for cgroupfs
+func fakeCGroup(t *testing.T) (string, *CGroup) {
+	root := filepath.Join(t.TempDir(), "jobworker")
+	cgroup := &CGroup{root: root, fake: true}
+	assert.Nil(t, cgroup.Setup(), "error setting up cgroup")
+	return root, cgroup
+}
+
+// clearFakeJobGroup helper func to delete the interface files of a job cgroup
+// in a fake root, which cgroupfs drops by itself when the cgroup is removed
+func clearFakeJobGroup(t *testing.T, path string) {
+	entries, err := os.ReadDir(path)
+	assert.Nil(t, err, "error reading job cgroup")
+	for _, entry := range entries {
+		assert.Nil(t, os.Remove(filepath.Join(path, entry.Name())))
+	}
+}
+
+func TestSetupCGroup(t *testing.T) {
+	root, _ := fakeCGroup(t)
+
+	assert.DirExists(t, root)
+	assert.Equal(t, Controllers, readCGroupFile(t, filepath.Join(root, SubtreeControlFile)))
+	assert.Equal(t, Controllers, readCGroupFile(t, filepath.Join(filepath.Dir(root), SubtreeControlFile)))
+
+	// nothing is created outside of a cgroup v2 mount
+	root = filepath.Join(t.TempDir(), "jobworker")
+	assert.NotNil(t, NewCGroup(root).Setup(), "expected error setting up cgroup on a plain directory")
+	assert.NoDirExists(t, root)
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(root), SubtreeControlFile))
+}
+
+func TestCreateJobGroup(t *testing.T) {
+	root, cgroup := fakeCGroup(t)
+
+	path, err := cgroup.CreateJobGroup("alice", "job1", DefaultLimits())
+	assert.Nil(t, err, "error creating job cgroup")
+	assert.Equal(t, filepath.Join(root, "alice", "job1"), path)
+
+	assert.Equal(t, Controllers, readCGroupFile(t, filepath.Join(root, "alice", SubtreeControlFile)))
+	assert.Equal(t, "100000 200000", readCGroupFile(t, filepath.Join(path, CPUMaxFile)))
+	assert.Equal(t, "134217728", readCGroupFile(t, filepath.Join(path, MEMMaxFile)))
+	assert.Equal(t, "max", readCGroupFile(t, filepath.Join(path, PidsMaxFile)))
+	assert.NoFileExists(t, filepath.Join(path, IOMaxFile), "io should not be limited by default")
+
+	_, err = cgroup.CreateJobGroup("alice", "job1", DefaultLimits())
+	assert.NotNil(t, err, "expected error creating duplicate job cgroup")
+
+	_, err = cgroup.CreateJobGroup("", "job2", DefaultLimits())
+	assert.NotNil(t, err, "expected error creating cgroup without user")
+}
+
+func TestCreateJobGroupWithLimits(t *testing.T) {
+	_, cgroup := fakeCGroup(t)
+
+	limits := Limits{
+		CPUQuota:  50000,
+		CPUPeriod: 100000,
+		Memory:    1048576,
+		Pids:      10,
+		IO: []IOLimit{
+			{Device: "8:0", ReadBPS: 2048},
+			{Device: "8:16", WriteIOPS: 100},
+		},
+	}
+	path, err := cgroup.CreateJobGroup("alice", "job1", limits)
+	assert.Nil(t, err, "error creating job cgroup")
+
+	assert.Equal(t, "50000 100000", readCGroupFile(t, filepath.Join(path, CPUMaxFile)))
+	assert.Equal(t, "1048576", readCGroupFile(t, filepath.Join(path, MEMMaxFile)))
+	assert.Equal(t, "10", readCGroupFile(t, filepath.Join(path, PidsMaxFile)))
+	assert.Equal(t, "8:0 rbps=2048 wbps=max riops=max wiops=max\n8:16 rbps=max wbps=max riops=max wiops=100\n",
+		readCGroupFile(t, filepath.Join(path, IOMaxFile)))
+}
+
+func TestRemoveJobGroup(t *testing.T) {
+	root, cgroup := fakeCGroup(t)
+
+	path, err := cgroup.CreateJobGroup("bob", "job1", DefaultLimits())
+	assert.Nil(t, err, "error creating job cgroup")
+
+	assert.NotNil(t, cgroup.RemoveJobGroup(path), "expected error removing a job cgroup that is not empty")
+	assert.DirExists(t, path)
+
+	clearFakeJobGroup(t, path)
+	assert.Nil(t, cgroup.RemoveJobGroup(path), "error removing job cgroup")
+	assert.NoDirExists(t, path)
+	assert.DirExists(t, filepath.Join(root, "bob"))
+
+	assert.Nil(t, cgroup.RemoveJobGroup(path), "removing a missing cgroup should not fail")
+}
+
+func TestOOMKilled(t *testing.T) {
+	_, cgroup := fakeCGroup(t)
+
+	path, err := cgroup.CreateJobGroup("alice", "job1", DefaultLimits())
+	assert.Nil(t, err, "error creating job cgroup")
+	assert.False(t, cgroup.OOMKilled(path), "missing memory.events should not be an oom kill")
+
+	events := filepath.Join(path, MEMEventsFile)
+	assert.Nil(t, os.WriteFile(events, []byte("low 0\nhigh 0\nmax 3\noom 1\noom_kill 0\n"), 0644))
+	assert.False(t, cgroup.OOMKilled(path))
+
+	assert.Nil(t, os.WriteFile(events, []byte("low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n"), 0644))
+	assert.True(t, cgroup.OOMKilled(path))
+}
+
+func TestKillJobGroup(t *testing.T) {
+	_, cgroup := fakeCGroup(t)
+
+	path, err := cgroup.CreateJobGroup("alice", "job1", DefaultLimits())
+	assert.Nil(t, err, "error creating job cgroup")
+	assert.True(t, cgroup.JobGroupEmpty(path), "missing cgroup.procs should be empty")
+
+	procs := filepath.Join(path, ProcsFile)
+	assert.Nil(t, os.WriteFile(procs, []byte("1234\n5678\n"), 0644))
+	assert.False(t, cgroup.JobGroupEmpty(path))
+	assert.Nil(t, os.WriteFile(procs, []byte(""), 0644))
+	assert.True(t, cgroup.JobGroupEmpty(path))
+
+	assert.Nil(t, cgroup.KillJobGroup(path), "error killing job cgroup")
+	assert.Equal(t, "1", readCGroupFile(t, filepath.Join(path, KillFile)))
+}
+
+func TestFreezeJobGroup(t *testing.T) {
+	_, cgroup := fakeCGroup(t)
+
+	path, err := cgroup.CreateJobGroup("alice", "job1", DefaultLimits())
+	assert.Nil(t, err, "error creating job cgroup")
+
+	assert.Nil(t, cgroup.FreezeJobGroup(path, true), "error freezing job cgroup")
+	assert.Equal(t, "1", readCGroupFile(t, filepath.Join(path, FreezeFile)))
+	assert.Nil(t, cgroup.FreezeJobGroup(path, false), "error thawing job cgroup")
+	assert.Equal(t, "0", readCGroupFile(t, filepath.Join(path, FreezeFile)))
+}
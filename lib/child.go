@@ -0,0 +1,221 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// childName is the argv[0] the job library re-executes its own binary with
+	// to set up a job's process before it runs the command
+	childName = "jobworker-child"
+	// childFailedCode is the exit code of a child that could not run the command
+	childFailedCode = 127
+)
+
+// childConfig is what the child process applies before it runs the job's command
+// Umask: file mode creation mask, unchanged when nil
+// Namespaces: the new namespaces the child was cloned into, set up by the child
+// Hostname: hostname of a new UTS namespace
+// Credential: user the command runs as, applied by the child since setting up
+// the namespaces needs root
+// RootFS: overlay the child makes its root, the command is then looked up in it
+// Dir: working directory inside RootFS
+// Seccomp: profile installed last, so the set up itself is not filtered
+// SignalFD: descriptor pid 1 of a pid namespace reports the signal that killed
+// the command on, since it cannot be killed by it itself
+type childConfig struct {
+	Umask      *uint32        `json:"umask,omitempty"`
+	Namespaces Namespaces     `json:"namespaces,omitempty"`
+	Hostname   string         `json:"hostname,omitempty"`
+	Credential *Credential    `json:"credential,omitempty"`
+	RootFS     *rootfsLayer   `json:"rootfs,omitempty"`
+	Dir        string         `json:"dir,omitempty"`
+	Seccomp    SeccompProfile `json:"seccomp,omitempty"`
+	SignalFD   int            `json:"signalFd,omitempty"`
+}
+
+// wrapChild() helper func to start cmd through the child process. The binary
+// running the job library is started again with the config and the resolved
+// command as arguments, and RunChild replaces it with the command
+func wrapChild(cmd *exec.Cmd, config childConfig) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to encode child config: %v", err)
+	}
+	args := append([]string{childName, string(data), cmd.Path}, cmd.Args...)
+	cmd.Path = "/proc/self/exe"
+	cmd.Args = args
+	return nil
+}
+
+// RunChild runs a job's command when the binary was started again by the job
+// library to set up the job's process, and returns straight away otherwise.
+// Programs that start jobs call it first thing in main
+func RunChild() {
+	if len(os.Args) < 4 || os.Args[0] != childName {
+		return
+	}
+
+	var config childConfig
+	if err := json.Unmarshal([]byte(os.Args[1]), &config); err != nil {
+		childFail(fmt.Errorf("failed to decode child config: %v", err))
+	}
+	if err := config.Namespaces.setup(config.Hostname, config.RootFS); err != nil {
+		childFail(err)
+	}
+	path := os.Args[2]
+	if config.RootFS != nil {
+		if err := config.enterDir(); err != nil {
+			childFail(err)
+		}
+		resolved, err := lookPath(path)
+		if err != nil {
+			childFail(err)
+		}
+		path = resolved
+	}
+	if config.Umask != nil {
+		unix.Umask(int(*config.Umask))
+	}
+	if config.Namespaces.PID {
+		os.Exit(runInit(path, os.Args[3:], config))
+	}
+
+	if config.Credential != nil {
+		if err := setCredential(*config.Credential); err != nil {
+			childFail(err)
+		}
+	}
+	if err := config.Seccomp.install(); err != nil {
+		childFail(err)
+	}
+	err := unix.Exec(path, os.Args[3:], os.Environ())
+	childFail(fmt.Errorf("failed to run %s: %v", path, err))
+}
+
+// enterDir() helper func to change to the working directory inside the new root
+func (c childConfig) enterDir() error {
+	dir := c.Dir
+	if dir == "" {
+		dir = "/"
+	}
+	if err := os.Chdir(dir); err != nil {
+		return fmt.Errorf("failed to change working directory: %v", err)
+	}
+	return nil
+}
+
+// setCredential() helper func to switch the child to the job's user. The
+// syscall package changes every thread of the process, unlike x/sys/unix
+func setCredential(credential Credential) error {
+	groups := make([]int, len(credential.Groups))
+	for i, group := range credential.Groups {
+		groups[i] = int(group)
+	}
+	if err := syscall.Setgroups(groups); err != nil {
+		return fmt.Errorf("failed to set groups: %v", err)
+	}
+	if err := syscall.Setgid(int(credential.GID)); err != nil {
+		return fmt.Errorf("failed to set gid: %v", err)
+	}
+	if err := syscall.Setuid(int(credential.UID)); err != nil {
+		return fmt.Errorf("failed to set uid: %v", err)
+	}
+	return nil
+}
+
+// runInit() helper func to run the command as the child of pid 1 of the job's
+// pid namespace and return its exit code. pid 1 ignores signals it has no handler
+// for and adopts every orphan of the namespace, so it cannot simply become the
+// command. The command stays in the job's process group so signals sent to the
+// job reach it directly, while pid 1 handles and ignores them. Leftover
+// processes are killed by the kernel when pid 1 exits. pid 1 installs the
+// seccomp profile itself, the command inherits it
+func runInit(path string, args []string, config childConfig) int {
+	if config.SignalFD > 0 {
+		unix.CloseOnExec(config.SignalFD)
+	}
+	signals := make(chan os.Signal, 16)
+	signal.Notify(signals)
+	go func() {
+		for range signals {
+		}
+	}()
+
+	if err := config.Seccomp.install(); err != nil {
+		childFail(err)
+	}
+
+	attr := &syscall.ProcAttr{
+		Env:   os.Environ(),
+		Files: []uintptr{0, 1, 2},
+		Sys:   &syscall.SysProcAttr{},
+	}
+	if config.Credential != nil {
+		attr.Sys.Credential = config.Credential.sysCredential()
+	}
+	pid, err := syscall.ForkExec(path, args, attr)
+	if err != nil {
+		childFail(fmt.Errorf("failed to run %s: %v", path, err))
+	}
+
+	for {
+		var status unix.WaitStatus
+		reaped, err := unix.Wait4(-1, &status, 0, nil)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			childFail(fmt.Errorf("failed to wait for %s: %v", path, err))
+		}
+		if reaped != pid {
+			continue
+		}
+		// pid 1 ignores the signal even when it sends it to itself, so it tells
+		// the server instead and, like a shell, exits with 128 plus the signal
+		if status.Signaled() {
+			if config.SignalFD > 0 {
+				report := os.NewFile(uintptr(config.SignalFD), "signal")
+				fmt.Fprintf(report, "%d", int(status.Signal()))
+				report.Close()
+			}
+			return 128 + int(status.Signal())
+		}
+		return status.ExitStatus()
+	}
+}
+
+// readSignal() helper func to get the signal pid 1 of a pid namespace reported
+// on r, 0 when the command was not killed by one or r is nil
+func readSignal(r *os.File) syscall.Signal {
+	if r == nil {
+		return 0
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0
+	}
+	sig, err := strconv.Atoi(string(data))
+	if err != nil {
+		return 0
+	}
+	return syscall.Signal(sig)
+}
+
+// childFail() helper func to report why the child could not run the command.
+// The job's stderr is the only way back to the server at this point
+func childFail(err error) {
+	fmt.Fprintf(os.Stderr, "jobworker: %v\n", err)
+	os.Exit(childFailedCode)
+}
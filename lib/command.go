@@ -0,0 +1,316 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// maxSymlinks is how many symlinks resolving a command can follow, as many as the kernel does
+const maxSymlinks = 40
+
+// codeVariablePrefixes and codeVariables are the environment variables a job
+// cannot set when a command policy restricts it, they load other code into
+// whatever the job runs
+var (
+	codeVariablePrefixes = []string{"LD_", "BASH_FUNC_"}
+	codeVariables        = []string{
+		"BASH_ENV", "ENV", "SHELLOPTS", "BASHOPTS", "PS4", "GCONV_PATH",
+		"PERL5OPT", "PERL5LIB", "PERLLIB",
+		"PYTHONPATH", "PYTHONSTARTUP", "PYTHONHOME",
+		"RUBYOPT", "RUBYLIB", "NODE_OPTIONS",
+	}
+)
+
+// PolicyAction is what a command policy does with the jobs a rule matches
+type PolicyAction string
+
+const (
+	// ActionAllow lets the job start
+	ActionAllow PolicyAction = "allow"
+	// ActionDeny rejects the job
+	ActionDeny PolicyAction = "deny"
+)
+
+// Validate checks the action exists
+func (a PolicyAction) Validate() error {
+	if a != ActionAllow && a != ActionDeny {
+		return fmt.Errorf("unknown action %q, expected allow or deny", string(a))
+	}
+	return nil
+}
+
+// CommandRule matches jobs by who starts them and what they run, every set field has to match
+// Users: usernames the rule applies to, everyone when Users and Groups are both empty
+// Groups: groups of the policy the rule applies to
+// Command: glob matched against the absolute path of the executable, any when empty
+// Args: regular expression matched against the arguments joined by spaces, any when empty
+// Limits: ceiling the job's limits have to be within, any when nil
+// Action: allow or deny
+// Reason: why the job is denied, sent back to the client
+type CommandRule struct {
+	Users   []string     `json:"users,omitempty"`
+	Groups  []string     `json:"groups,omitempty"`
+	Command string       `json:"command,omitempty"`
+	Args    string       `json:"args,omitempty"`
+	Limits  *Limits      `json:"limits,omitempty"`
+	Action  PolicyAction `json:"action"`
+	Reason  string       `json:"reason,omitempty"`
+}
+
+// CommandPolicy is the administrator defined list of commands users can run.
+// Rules are checked in order and the first one matching a job decides
+// Default: action when no rule matches, allow when empty
+// Groups: named lists of usernames rules can refer to
+// Rules: the rules, in order
+type CommandPolicy struct {
+	Default PolicyAction        `json:"default,omitempty"`
+	Groups  map[string][]string `json:"groups,omitempty"`
+	Rules   []CommandRule       `json:"rules,omitempty"`
+}
+
+// Validate checks the rules are well formed
+func (p CommandPolicy) Validate() error {
+	if p.Default != "" {
+		if err := p.Default.Validate(); err != nil {
+			return fmt.Errorf("default: %v", err)
+		}
+	}
+	for i, rule := range p.Rules {
+		if err := rule.Action.Validate(); err != nil {
+			return fmt.Errorf("rule %d: %v", i+1, err)
+		}
+		for _, group := range rule.Groups {
+			if _, ok := p.Groups[group]; !ok {
+				return fmt.Errorf("rule %d: unknown group %q", i+1, group)
+			}
+		}
+		if _, err := filepath.Match(rule.Command, ""); err != nil {
+			return fmt.Errorf("rule %d: invalid command pattern %q: %v", i+1, rule.Command, err)
+		}
+		if _, err := regexp.Compile(rule.Args); err != nil {
+			return fmt.Errorf("rule %d: invalid args pattern: %v", i+1, err)
+		}
+		if rule.Limits != nil {
+			if err := rule.Limits.Validate(); err != nil {
+				return fmt.Errorf("rule %d: %v", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Check returns why a user cannot run the executable at path with args, env
+// and limits, nil when the job is allowed. env is what the job adds to the
+// environment, variables changing the code an executable runs are denied
+// whenever the policy restricts anything, else any allowed binary would run
+// whatever they point to
+func (p CommandPolicy) Check(username string, path string, args []string, env []string, limits Limits) error {
+	if len(p.Rules) > 0 || p.Default == ActionDeny {
+		for _, variable := range env {
+			key := strings.SplitN(variable, "=", 2)[0]
+			if codeVariable(key) {
+				return fmt.Errorf("%s cannot set %s: it changes the code %s runs", username, key, path)
+			}
+		}
+	}
+	for i, rule := range p.Rules {
+		if !p.matches(rule, username, path, args, limits) {
+			continue
+		}
+		if rule.Action == ActionAllow {
+			return nil
+		}
+		reason := rule.Reason
+		if reason == "" {
+			reason = fmt.Sprintf("denied by rule %d", i+1)
+		}
+		return fmt.Errorf("%s cannot run %s: %s", username, path, reason)
+	}
+	if p.Default == ActionDeny {
+		return fmt.Errorf("%s cannot run %s: not allowed by any rule", username, path)
+	}
+	return nil
+}
+
+// matches() helper func to check if a rule applies to a job
+func (p CommandPolicy) matches(rule CommandRule, username string, path string, args []string, limits Limits) bool {
+	if (len(rule.Users) > 0 || len(rule.Groups) > 0) && !contains(rule.Users, username) && !p.inGroups(rule.Groups, username) {
+		return false
+	}
+	if rule.Command != "" {
+		if ok, _ := filepath.Match(rule.Command, path); !ok {
+			return false
+		}
+	}
+	if rule.Args != "" {
+		if ok, _ := regexp.MatchString(rule.Args, strings.Join(args, " ")); !ok {
+			return false
+		}
+	}
+	if rule.Limits != nil && limits.Exceeds(*rule.Limits) != nil {
+		return false
+	}
+	return true
+}
+
+// ResolveCommand returns the absolute path of the executable a job's command
+// runs, to check it against a CommandPolicy and then run it through
+// ProcessOptions.Path. The command is looked up in PATH and cleaned, but its
+// symlinks are not followed: a symlink or an applet of a multi-call binary
+// like busybox is matched by its own path, not by the file it points to. The
+// command of a job with a root filesystem is looked up in the image the way
+// the job would, and its path inside the image is returned. The command is
+// kept as is when it is not found
+func ResolveCommand(command string, process ProcessOptions) string {
+	if process.RootFS != nil {
+		return resolveInImage(command, process)
+	}
+	path := command
+	if !strings.Contains(command, "/") {
+		found, err := exec.LookPath(command)
+		if err != nil {
+			return command
+		}
+		path = found
+	}
+	if !filepath.IsAbs(path) {
+		// like exec.Cmd, a relative path is relative to the working directory
+		dir := process.Dir
+		if dir == "" {
+			wd, err := os.Getwd()
+			if err != nil {
+				return path
+			}
+			dir = wd
+		}
+		path = filepath.Join(dir, path)
+	}
+	return filepath.Clean(path)
+}
+
+// resolveInImage() helper func to find the executable a job's command runs in
+// its root filesystem, through the job's PATH like lookPath
+func resolveInImage(command string, process ProcessOptions) string {
+	if strings.Contains(command, "/") {
+		if filepath.IsAbs(command) {
+			return filepath.Clean(command)
+		}
+		// the child changes to the working directory before running the command
+		return filepath.Join("/", process.Dir, command)
+	}
+	for _, dir := range filepath.SplitList(imagePath(process)) {
+		// like exec.LookPath, relative folders of PATH are not searched
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+		path := filepath.Join(dir, command)
+		if imageExecutable(process.RootFS.Image, path) {
+			return path
+		}
+	}
+	return command
+}
+
+// imageExecutable() helper func to check if path is an executable file of the
+// image, following the image's symlinks inside of it and not on the host
+func imageExecutable(image string, path string) bool {
+	resolved, err := resolveInRoot(image, path)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(image, resolved))
+	return err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+}
+
+// imagePath() helper func to get the PATH a job's command is looked up in
+// inside its root filesystem, the last one of its environment wins
+func imagePath(process ProcessOptions) string {
+	path := ""
+	for _, variable := range process.environ() {
+		if strings.HasPrefix(variable, "PATH=") {
+			path = strings.TrimPrefix(variable, "PATH=")
+		}
+	}
+	if path == "" {
+		return defaultPath
+	}
+	return path
+}
+
+// resolveInRoot() helper func to resolve the symlinks of the absolute path as
+// if root was /, links to absolute paths and .. never leave root
+func resolveInRoot(root string, path string) (string, error) {
+	resolved := "/"
+	rest := strings.Split(path, "/")
+	links := 0
+	for len(rest) > 0 {
+		part := rest[0]
+		rest = rest[1:]
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+		next := filepath.Join(resolved, part)
+		info, err := os.Lstat(filepath.Join(root, next))
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+		links++
+		if links > maxSymlinks {
+			return "", fmt.Errorf("too many levels of symbolic links in %s", path)
+		}
+		target, err := os.Readlink(filepath.Join(root, next))
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(target) {
+			resolved = "/"
+		}
+		rest = append(strings.Split(target, "/"), rest...)
+	}
+	return resolved, nil
+}
+
+// codeVariable() helper func to check if an environment variable makes the
+// dynamic loader, a shell or an interpreter load code of the job's choosing
+func codeVariable(key string) bool {
+	for _, prefix := range codeVariablePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return contains(codeVariables, key)
+}
+
+// inGroups() helper func to check if a user is a member of one of the groups
+func (p CommandPolicy) inGroups(groups []string, username string) bool {
+	for _, group := range groups {
+		if contains(p.Groups[group], username) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains() helper func to check if a list has a value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
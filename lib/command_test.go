@@ -0,0 +1,117 @@
+package jobworker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandPolicyCheck(t *testing.T) {
+	policy := CommandPolicy{
+		Default: ActionDeny,
+		Groups:  map[string][]string{"ops": {"carl"}},
+		Rules: []CommandRule{
+			{Command: "/usr/bin/rm", Args: `(^| )-[a-zA-Z]*r`, Action: ActionDeny, Reason: "recursive rm is not allowed"},
+			{Groups: []string{"ops"}, Action: ActionAllow},
+			{Users: []string{"alice"}, Command: "/usr/bin/*", Limits: &Limits{Memory: 1024}, Action: ActionAllow},
+		},
+	}
+	assert.Nil(t, policy.Validate())
+
+	// carl can run anything but a recursive rm
+	assert.Nil(t, policy.Check("carl", "/usr/bin/rm", []string{"file"}, nil, Limits{}))
+	err := policy.Check("carl", "/usr/bin/rm", []string{"-rf", "/"}, nil, Limits{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "recursive rm is not allowed")
+	assert.Nil(t, policy.Check("carl", "/opt/tool", nil, nil, Limits{}))
+
+	// alice only within her memory ceiling and under /usr/bin
+	assert.Nil(t, policy.Check("alice", "/usr/bin/ls", nil, nil, Limits{Memory: 512}))
+	assert.NotNil(t, policy.Check("alice", "/usr/bin/ls", nil, nil, Limits{Memory: 2048}))
+	assert.NotNil(t, policy.Check("alice", "/usr/bin/ls", nil, nil, Limits{}))
+	assert.NotNil(t, policy.Check("alice", "/opt/tool", nil, nil, Limits{Memory: 512}))
+	assert.NotNil(t, policy.Check("bob", "/usr/bin/ls", nil, nil, Limits{Memory: 512}))
+
+	// variables loading other code are denied, even for commands that are allowed
+	assert.Nil(t, policy.Check("carl", "/usr/bin/ls", nil, []string{"TERM=xterm"}, Limits{}))
+	for _, variable := range []string{"LD_PRELOAD=/tmp/evil.so", "LD_LIBRARY_PATH=/tmp", "BASH_ENV=/tmp/evil.sh", "BASH_FUNC_ls%%=() { id; }"} {
+		err = policy.Check("carl", "/usr/bin/ls", nil, []string{"TERM=xterm", variable}, Limits{})
+		assert.NotNil(t, err, variable)
+	}
+	assert.Contains(t, err.Error(), "carl cannot set BASH_FUNC_ls%%")
+
+	// everything is allowed without rules
+	assert.Nil(t, CommandPolicy{}.Check("bob", "/usr/bin/ls", nil, nil, Limits{}))
+	assert.Nil(t, CommandPolicy{}.Check("bob", "/usr/bin/ls", nil, []string{"LD_PRELOAD=/tmp/lib.so"}, Limits{}))
+
+	assert.NotNil(t, CommandPolicy{Default: "maybe"}.Validate())
+	assert.NotNil(t, CommandPolicy{Rules: []CommandRule{{Action: "maybe"}}}.Validate())
+	assert.NotNil(t, CommandPolicy{Rules: []CommandRule{{Groups: []string{"ops"}, Action: ActionAllow}}}.Validate())
+	assert.NotNil(t, CommandPolicy{Rules: []CommandRule{{Command: "[", Action: ActionAllow}}}.Validate())
+	assert.NotNil(t, CommandPolicy{Rules: []CommandRule{{Args: "(", Action: ActionAllow}}}.Validate())
+}
+
+func TestResolveCommand(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	assert.Nil(t, err)
+	assert.Equal(t, sh, ResolveCommand("sh", ProcessOptions{}))
+	assert.Equal(t, "/bin/sh", ResolveCommand("/bin/../bin/sh", ProcessOptions{}))
+	assert.Equal(t, "/bin/sh", ResolveCommand("./sh", ProcessOptions{Dir: "/bin"}))
+
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(wd, "missing"), ResolveCommand("./missing", ProcessOptions{}))
+	assert.Equal(t, "missing-command", ResolveCommand("missing-command", ProcessOptions{}))
+
+	// a symlink is checked by its own path, retargeting it cannot change what was checked
+	dir := t.TempDir()
+	link := filepath.Join(dir, "ls")
+	assert.Nil(t, os.Symlink("/bin/sh", link))
+	assert.Equal(t, link, ResolveCommand("./ls", ProcessOptions{Dir: dir}))
+
+	// commands of a root filesystem are looked up in the image, by the paths the job names
+	image := t.TempDir()
+	for _, dir := range []string{"bin", "usr/bin", "opt"} {
+		assert.Nil(t, os.MkdirAll(filepath.Join(image, dir), 0755))
+	}
+	assert.Nil(t, os.WriteFile(filepath.Join(image, "bin", "busybox"), nil, 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(image, "usr/bin", "notes"), nil, 0644))
+	assert.Nil(t, os.Symlink("/bin/busybox", filepath.Join(image, "bin", "sh")))
+	assert.Nil(t, os.Symlink("../../bin/busybox", filepath.Join(image, "usr/bin", "rm")))
+	assert.Nil(t, os.Symlink("/bin/sh", filepath.Join(image, "opt", "sh")))
+	assert.Nil(t, os.Symlink("loop", filepath.Join(image, "opt", "loop")))
+
+	rootfs := ProcessOptions{RootFS: &RootFS{Image: image, Dir: t.TempDir()}}
+	assert.Equal(t, "/bin/sh", ResolveCommand("sh", rootfs))
+	assert.Equal(t, "/usr/bin/rm", ResolveCommand("rm", rootfs))
+	assert.Equal(t, "/usr/bin/rm", ResolveCommand("/bin/../usr/bin/rm", rootfs))
+	assert.Equal(t, "/bin/sh", ResolveCommand("../bin/sh", ProcessOptions{RootFS: rootfs.RootFS, Dir: "/opt"}))
+	assert.Equal(t, "notes", ResolveCommand("notes", rootfs), "files that are not executable are not found")
+	assert.Equal(t, "missing", ResolveCommand("missing", rootfs))
+
+	// the job's PATH decides which directories are searched, symlink loops are not found
+	rootfs.Env = []string{"PATH=/opt"}
+	assert.Equal(t, "/opt/sh", ResolveCommand("sh", rootfs))
+	assert.Equal(t, "loop", ResolveCommand("loop", rootfs))
+	rootfs.Env = []string{"PATH=/opt", "PATH=/usr/bin"}
+	assert.Equal(t, "/usr/bin/rm", ResolveCommand("rm", rootfs))
+
+	// the applets of a multi-call binary are told apart, denying one does not
+	// depend on the others and allowing one does not allow them all
+	rootfs.Env = nil
+	policy := CommandPolicy{
+		Default: ActionDeny,
+		Rules: []CommandRule{
+			{Command: "/usr/bin/rm", Action: ActionDeny, Reason: "rm is not allowed"},
+			{Command: "/bin/sh", Action: ActionAllow},
+		},
+	}
+	err = policy.Check("alice", ResolveCommand("rm", rootfs), nil, nil, Limits{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "rm is not allowed")
+	assert.Nil(t, policy.Check("alice", ResolveCommand("sh", rootfs), nil, nil, Limits{}))
+	assert.NotNil(t, policy.Check("alice", "/bin/busybox", nil, nil, Limits{}))
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// Credential is the local user every process of a job runs as
+// UID: user id, cannot be root
+// GID: primary group id, cannot be root
+// Groups: supplementary group ids, none when empty
+type Credential struct {
+	UID    uint32   `json:"uid"`
+	GID    uint32   `json:"gid"`
+	Groups []uint32 `json:"groups,omitempty"`
+}
+
+// Validate checks the credential does not give the job root
+func (c Credential) Validate() error {
+	if c.UID == 0 || c.GID == 0 {
+		return fmt.Errorf("jobs cannot run as uid 0 or gid 0")
+	}
+	for _, group := range c.Groups {
+		if group == 0 {
+			return fmt.Errorf("jobs cannot have group 0")
+		}
+	}
+	return nil
+}
+
+// sysCredential() helper func to convert the credential for exec.Cmd
+func (c Credential) sysCredential() *syscall.Credential {
+	// a nil Groups would make the process keep the server's supplementary groups
+	groups := append([]uint32{}, c.Groups...)
+	return &syscall.Credential{Uid: c.UID, Gid: c.GID, Groups: groups}
+}
+
+// UserMap is the administrator defined mapping of users, the CommonName of their
+// certificates, to the local users their jobs run as
+// Users: credential of each user
+// Default: credential of users missing from Users, their jobs are refused when nil
+type UserMap struct {
+	Users   map[string]Credential `json:"users"`
+	Default *Credential           `json:"default,omitempty"`
+}
+
+// Validate checks every credential of the map
+func (m UserMap) Validate() error {
+	for username, credential := range m.Users {
+		if err := credential.Validate(); err != nil {
+			return fmt.Errorf("invalid credential of %s: %v", username, err)
+		}
+	}
+	if m.Default != nil {
+		if err := m.Default.Validate(); err != nil {
+			return fmt.Errorf("invalid default credential: %v", err)
+		}
+	}
+	return nil
+}
+
+// Lookup returns the credential the user's jobs run as
+func (m UserMap) Lookup(username string) (*Credential, error) {
+	if credential, ok := m.Users[username]; ok {
+		return &credential, nil
+	}
+	if m.Default != nil {
+		credential := *m.Default
+		return &credential, nil
+	}
+	return nil, fmt.Errorf("%s is not mapped to a local user", username)
+}
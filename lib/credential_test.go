@@ -0,0 +1,61 @@
+package jobworker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nobody is the uid and gid of the nobody user on most distributions
+const nobody = 65534
+
+func TestUserMapLookup(t *testing.T) {
+	users := UserMap{Users: map[string]Credential{
+		"alice": {UID: 1001, GID: 1001, Groups: []uint32{100}},
+	}}
+	assert.Nil(t, users.Validate())
+
+	credential, err := users.Lookup("alice")
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1001), credential.UID)
+	assert.Equal(t, []uint32{100}, credential.Groups)
+
+	_, err = users.Lookup("bob")
+	assert.NotNil(t, err, "expected error looking up an unmapped user")
+	users.Default = &Credential{UID: nobody, GID: nobody}
+	credential, err = users.Lookup("bob")
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(nobody), credential.UID)
+
+	assert.NotNil(t, UserMap{Users: map[string]Credential{"root": {UID: 0, GID: 1}}}.Validate())
+	assert.NotNil(t, UserMap{Default: &Credential{UID: 1, GID: 0}}.Validate())
+	assert.NotNil(t, Credential{UID: 1, GID: 1, Groups: []uint32{0}}.Validate())
+}
+
+func TestJobCredential(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("running jobs as another user needs root")
+	}
+	dir := t.TempDir()
+	assert.Nil(t, os.Chmod(dir, 0755))
+	private := filepath.Join(dir, "private")
+	assert.Nil(t, os.WriteFile(private, []byte("secret"), 0600))
+
+	process := ProcessOptions{Credential: &Credential{UID: nobody, GID: nobody, Groups: []uint32{nobody - 1}}}
+	newJob := runJob(t, []string{"sh", "-c", "id -u; id -g; id -G"}, JobOptions{Process: process})
+	assert.Equal(t, StateSucceeded, newJob.State())
+	assert.Equal(t, "65534\n65534\n65534 65533\n", string(readFrom(newJob.output, 0)))
+
+	// other users' files are out of reach
+	newJob = runJob(t, []string{"cat", private}, JobOptions{Process: process})
+	assert.Equal(t, StateFailed, newJob.State())
+	newJob = runJob(t, []string{"touch", filepath.Join(dir, "new")}, JobOptions{Process: process})
+	assert.Equal(t, StateFailed, newJob.State())
+
+	// the child process started for a umask runs as the user too
+	process.Umask = umask(0077)
+	newJob = runJob(t, []string{"sh", "-c", "id -u; umask"}, JobOptions{Process: process})
+	assert.Equal(t, "65534\n0077\n", string(readFrom(newJob.output, 0)))
+}
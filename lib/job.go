@@ -3,40 +3,152 @@
 package jobworker
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"os/exec"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/sys/unix"
 )
 
+// TerminationReason describes why a job ended
+type TerminationReason string
+
 const (
-	RunningStatus = "running"
-	StoppedStatus = "stopped"
+	ReasonNone        TerminationReason = ""
+	ReasonExited      TerminationReason = "exited"
+	ReasonSignaled    TerminationReason = "signaled"
+	ReasonStopped     TerminationReason = "stopped"
+	ReasonOOMKilled   TerminationReason = "oom_killed"
+	ReasonTimedOut    TerminationReason = "timed_out"
+	ReasonStartFailed TerminationReason = "start_failed"
+	ReasonLost        TerminationReason = "lost"
 )
 
+const (
+	// DefaultStopTimeout is how long a stopped job has to exit before it is killed
+	DefaultStopTimeout = 10 * time.Second
+	// MaxStopTimeout is the longest a stopped job can be given to exit, callers
+	// of Stop wait for it
+	MaxStopTimeout = 5 * time.Minute
+	// killTimeout is how long a killed job has to disappear before its cgroup is removed
+	killTimeout = 2 * time.Second
+)
+
+// JobResult represents the outcome of a job
+// ExitCode: -1 while running or when the job was killed by a signal
+// Signal: name of the signal that killed the job, i.e. SIGKILL
+// Reason: empty until the job has ended
+type JobResult struct {
+	ExitCode  int
+	Signal    string
+	StartTime time.Time
+	EndTime   time.Time
+	Reason    TerminationReason
+}
+
 type JobInfo struct {
 	JobID      string
-	status     string
+	pid        int
+	cgroupPath string
+	state      JobState
+	history    []StateChange
+	done       chan struct{}
 	cancelJob  context.CancelFunc
-	outputChan chan string
 	command    []string
-	output     *bytes.Buffer
+	output     *Broadcaster
+	username   string
+	cgroup     *CGroup
+	limits     Limits
+	timeout    time.Duration
+	outputOpts OutputOptions
+	process    ProcessOptions
+	// stdin: the job reads stdinReader, written to through stdinWriter
+	// tty: stdinReader and stdinWriter are the slave and master of a pseudo-terminal
+	stdin       bool
+	tty         bool
+	stdinReader *os.File
+	stdinWriter *os.File
+	mutex       sync.Mutex
+	result      JobResult
+	store       JobStore
+	// saveMutex orders the saves of the job, unsaved is set while a change is not saved yet
+	saveMutex sync.Mutex
+	unsaved   bool
 }
 
-func NewJob(command []string) (*JobInfo, error) {
-	jobID := uuid.New().String()
-	outChannel := make(chan string)
-	outBuf := bytes.NewBuffer([]byte{})
+// JobOptions holds the optional settings of a new job
+// Username: owner of the job, used to name the user's cgroup
+// CGroup: when set, the job runs in <root>/<username>/<jobID>/
+// Limits: resource limits written to the job's cgroup
+// Timeout: the job is killed after running this long, zero for no timeout
+// Output: where the output is kept and how much of it
+// Stdin: the job's stdin is fed through WriteStdin instead of /dev/null
+// TTY: the job runs in a pseudo-terminal fed through WriteStdin, its output is the raw terminal output
+// TerminalSize: initial size of the pseudo-terminal
+// Process: environment, working directory, umask, user and namespaces of the command
+type JobOptions struct {
+	Username     string
+	CGroup       *CGroup
+	Limits       Limits
+	Timeout      time.Duration
+	Output       OutputOptions
+	Stdin        bool
+	TTY          bool
+	TerminalSize TerminalSize
+	Process      ProcessOptions
+}
+
+// StopOptions sets how a running job is stopped
+// Signal: sent to every process of the job first, SIGTERM when zero
+// Timeout: how long the job has to exit before everything is killed, DefaultStopTimeout
+// when zero and at most MaxStopTimeout
+type StopOptions struct {
+	Signal  syscall.Signal
+	Timeout time.Duration
+}
+
+func NewJob(command []string, opts JobOptions) (*JobInfo, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("command cannot be empty")
+	}
 
+	jobID := uuid.New().String()
+	output, err := NewOutput(jobID, opts.Output)
+	if err != nil {
+		return nil, err
+	}
 	job := JobInfo{
 		JobID:      jobID,
 		command:    command,
-		outputChan: outChannel,
-		output:     outBuf,
+		output:     output,
+		username:   opts.Username,
+		cgroup:     opts.CGroup,
+		limits:     opts.Limits,
+		timeout:    opts.Timeout,
+		outputOpts: opts.Output,
+		process:    opts.Process,
+		stdin:      opts.Stdin || opts.TTY,
+		tty:        opts.TTY,
+		result:     JobResult{ExitCode: -1},
+		state:      StatePending,
+		history:    []StateChange{{State: StatePending, Time: time.Now()}},
+		done:       make(chan struct{}),
+	}
+	switch {
+	case opts.TTY:
+		err = job.openPTY(opts.TerminalSize)
+	case opts.Stdin:
+		err = job.openStdin()
+	}
+	if err != nil {
+		output.Close()
+		return nil, err
 	}
 
 	return &job, nil
@@ -46,90 +158,504 @@ func NewJob(command []string) (*JobInfo, error) {
 func (j *JobInfo) execute(ctx context.Context) error {
 	log.Printf("executing")
 	cmd := exec.CommandContext(ctx, j.command[0], j.command[1:]...)
-	//cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
-	out, err := cmd.StdoutPipe()
+	// the job gets its own process group so signals reach everything it starts
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	capture := j.captureOutput
+	if j.tty {
+		// a new session is also a new process group, with the terminal as its controlling tty
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+		capture = j.captureTTY
+	}
+	var layer *rootfsLayer
+	if rootfs := j.process.RootFS; rootfs != nil {
+		// removed last, once every process of the job is gone
+		defer func() {
+			if err := rootfs.removeLayer(j.JobID); err != nil {
+				log.Printf("%v", err)
+			}
+		}()
+		created, err := rootfs.createLayer(j.JobID)
+		if err != nil {
+			j.output.Close()
+			return err
+		}
+		layer = &created
+	}
+	signals, err := j.process.apply(cmd, j.JobID, layer)
 	if err != nil {
-		fmt.Errorf("%v", err)
+		j.output.Close()
 		return err
 	}
+	if signals != nil {
+		defer signals.Close()
+	}
+	// only the child keeps the write ends once it has started
+	defer closeFiles(cmd.ExtraFiles)
+	stdout, stderr, err := capture()
+	if err != nil {
+		j.output.Close()
+		return err
+	}
+	defer stdout.Close()
+	defer stderr.Close()
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	j.mutex.Lock()
+	if j.stdinReader != nil {
+		cmd.Stdin = j.stdinReader
+	}
+	j.mutex.Unlock()
+
+	var cgroupPath string
+	if j.cgroup != nil {
+		cgroupPath, err = j.cgroup.CreateJobGroup(j.username, j.JobID, j.limits)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := j.cgroup.RemoveJobGroup(cgroupPath); err != nil {
+				log.Printf("%v", err)
+			}
+		}()
+
+		// the process is cloned directly into the cgroup so the limits
+		// apply before the command runs
+		cgroupDir, err := os.Open(cgroupPath)
+		if err != nil {
+			return fmt.Errorf("failed to open cgroup: %v", err)
+		}
+		defer cgroupDir.Close()
+		cmd.SysProcAttr.UseCgroupFD = true
+		cmd.SysProcAttr.CgroupFD = int(cgroupDir.Fd())
+	}
+	// a cancelled job is killed with everything it started, not only the first process
+	cmd.Cancel = func() error {
+		j.killGroup(cmd.Process.Pid, cgroupPath)
+		return nil
+	}
 
 	if err := cmd.Start(); err != nil {
-		fmt.Errorf("%v", err)
-		return err
+		return fmt.Errorf("failed to start command: %v", err)
+	}
+	pid := cmd.Process.Pid
+	// only the process holds the pipes now, so the readers finish when it exits
+	stdout.Close()
+	stderr.Close()
+	closeFiles(cmd.ExtraFiles)
+	j.closeStdinReader()
+	j.mutex.Lock()
+	j.pid = pid
+	j.cgroupPath = cgroupPath
+	j.result.StartTime = time.Now()
+	j.persist()
+	j.mutex.Unlock()
+	j.save()
+
+	if err := cmd.Wait(); err != nil {
+		log.Printf("job %s: %v", j.JobID, err)
+	}
+	// read before the clean up below, which can outlast a deadline the command beat
+	timedOut := ctx.Err() == context.DeadlineExceeded
+	if j.State() == StateStopping {
+		// the rest of the job gets what is left of the stop timeout to exit
+		j.waitGroup(ctx, pid, cgroupPath)
+	}
+	// processes the command left running end with the job, they would
+	// otherwise keep the cgroup and the root filesystem layer busy
+	j.killGroup(pid, cgroupPath)
+	waitCtx, cancel := context.WithTimeout(context.Background(), killTimeout)
+	j.waitGroup(waitCtx, pid, cgroupPath)
+	cancel()
+	j.finish(timedOut, cmd.ProcessState, readSignal(signals), cgroupPath)
+	return nil
+}
+
+// killGroup() helper func to SIGKILL every process of the job, through
+// cgroup.kill when the job has a cgroup and its process group otherwise
+func (j *JobInfo) killGroup(pgid int, cgroupPath string) {
+	if cgroupPath != "" {
+		if err := j.cgroup.KillJobGroup(cgroupPath); err != nil {
+			log.Printf("job %s: %v", j.JobID, err)
+		}
+	}
+	if err := signalGroup(pgid, syscall.SIGKILL); err != nil {
+		log.Printf("job %s: %v", j.JobID, err)
+	}
+}
+
+// waitGroup() helper func to wait until every process of the job has exited or ctx is done
+func (j *JobInfo) waitGroup(ctx context.Context, pgid int, cgroupPath string) {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		empty := unix.Kill(-pgid, 0) == unix.ESRCH
+		if empty && cgroupPath != "" {
+			empty = j.cgroup.JobGroupEmpty(cgroupPath)
+		}
+		if empty {
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
 	}
-	fmt.Println("execute completed")
+}
+
+// closeFiles() helper func to close every file of a list
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
+
+// finish() helper func to record how the job ended. timedOut is whether the
+// timeout had passed when the command exited, sig is the signal that killed
+// the command when pid 1 of its pid namespace reported one
+func (j *JobInfo) finish(timedOut bool, state *os.ProcessState, sig syscall.Signal, cgroupPath string) {
+	oomKilled := j.cgroup != nil && j.cgroup.OOMKilled(cgroupPath)
 
-	go func() {
-		defer close(j.outputChan)
-		scan := bufio.NewScanner(out)
-		for scan.Scan() {
-			m := scan.Text()
-			j.output.WriteString(fmt.Sprintf("%s\n", m))
-			j.outputChan <- m
+	defer j.save()
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.result.EndTime = time.Now()
+	j.result.ExitCode = state.ExitCode()
+
+	signaled := false
+	if ws, ok := state.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		signaled = true
+		j.result.Signal = unix.SignalName(ws.Signal())
+	} else if sig != 0 {
+		signaled = true
+		j.result.ExitCode = -1
+		j.result.Signal = unix.SignalName(sig)
+	}
+
+	next := StateFailed
+	switch {
+	case j.state == StateStopping:
+		j.result.Reason = ReasonStopped
+		next = StateStopped
+	case timedOut:
+		j.result.Reason = ReasonTimedOut
+		next = StateKilled
+	case oomKilled:
+		j.result.Reason = ReasonOOMKilled
+		next = StateKilled
+	case signaled:
+		j.result.Reason = ReasonSignaled
+		next = StateKilled
+	default:
+		j.result.Reason = ReasonExited
+		if state.Success() {
+			next = StateSucceeded
 		}
-	}()
+	}
+	if err := j.transition(next); err != nil {
+		log.Printf("%v", err)
+	}
+}
 
-	cmd.Wait()
-	fmt.Println("RETURNING: execute wait done")
+// transition() helper func to move the job to its next state.
+// The caller must hold the mutex
+func (j *JobInfo) transition(next JobState) error {
+	if !j.state.CanTransition(next) {
+		return fmt.Errorf("job %s cannot go from %s to %s", j.JobID, j.state, next)
+	}
+	j.state = next
+	j.history = append(j.history, StateChange{State: next, Time: time.Now()})
+	j.persist()
 	return nil
 }
 
-// Start - starts a job
+// record() helper func to copy the job's metadata for the store.
+// The caller must hold the mutex
+func (j *JobInfo) record() JobRecord {
+	history := make([]StateChange, len(j.history))
+	copy(history, j.history)
+	return JobRecord{
+		JobID:    j.JobID,
+		Username: j.username,
+		Command:  j.command,
+		Limits:   j.limits,
+		Timeout:  j.timeout,
+		Output:   j.outputOpts,
+		State:    j.state,
+		History:  history,
+		Result:   j.result,
+	}
+}
+
+// persist() helper func to mark the job's metadata as changed when it has a
+// store, save() writes it once the mutex is released. The caller must hold the mutex
+func (j *JobInfo) persist() {
+	if j.store != nil {
+		j.unsaved = true
+	}
+}
+
+// save() helper func to write the job's latest metadata to its store. The saves
+// are ordered by saveMutex so the mutex is not held while the store syncs to disk
+func (j *JobInfo) save() {
+	j.saveMutex.Lock()
+	defer j.saveMutex.Unlock()
+	j.mutex.Lock()
+	if !j.unsaved {
+		j.mutex.Unlock()
+		return
+	}
+	j.unsaved = false
+	record := j.record()
+	j.mutex.Unlock()
+	if err := j.store.SaveJob(record); err != nil {
+		log.Printf("job %s: %v", j.JobID, err)
+	}
+}
+
+// setStore() helper func to save the job and persist its changes from then on.
+// The job is left without a store when the first save fails
+func (j *JobInfo) setStore(store JobStore) error {
+	j.saveMutex.Lock()
+	defer j.saveMutex.Unlock()
+	j.mutex.Lock()
+	record := j.record()
+	j.mutex.Unlock()
+	if err := store.SaveJob(record); err != nil {
+		return err
+	}
+	j.mutex.Lock()
+	j.store = store
+	j.mutex.Unlock()
+	return nil
+}
+
+// Start - starts a job and blocks until it has ended
 func (jw *JobInfo) Start() {
 	log.Printf("Start job")
-	ctx, cancel := context.WithCancel(context.Background())
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if jw.timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), jw.timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	defer cancel()
+
+	jw.mutex.Lock()
+	if err := jw.transition(StateRunning); err != nil {
+		jw.mutex.Unlock()
+		log.Printf("cannot start job: %v", err)
+		return
+	}
 	jw.cancelJob = cancel
-	jw.status = RunningStatus
-	fmt.Printf("status is %s\n", jw.status)
+	jw.mutex.Unlock()
+	jw.save()
+	defer close(jw.done)
+	defer jw.closeStdin()
+
 	err := jw.execute(ctx)
 	if err != nil {
-		fmt.Printf("error encountered in job")
+		log.Printf("error encountered in job: %v", err)
+		jw.mutex.Lock()
+		jw.result.EndTime = time.Now()
+		jw.result.Reason = ReasonStartFailed
+		next := StateFailed
+		if jw.state == StateStopping {
+			next = StateStopped
+		}
+		jw.transition(next)
+		jw.mutex.Unlock()
+		jw.save()
 		return
 	}
-	fmt.Println("start execute done")
-	jw.status = StoppedStatus
-	fmt.Printf("status is %s\n", jw.status)
-	fmt.Println("start job done")
-
-	return
 }
 
-// Stop - stop a job
-func (jw *JobInfo) Stop() {
-	jw.status = StoppedStatus
-	jw.cancelJob()
-	jw.outputChan <- "Job has been stopped by user\n"
-	return
+// Stop - stop a job and wait for it to exit. The signal in opts is sent to every
+// process of the job, whatever is left after the timeout is killed
+func (jw *JobInfo) Stop(opts StopOptions) error {
+	if opts.Timeout < 0 {
+		return fmt.Errorf("stop timeout cannot be negative")
+	}
+	if opts.Timeout > MaxStopTimeout {
+		return fmt.Errorf("stop timeout cannot be longer than %v", MaxStopTimeout)
+	}
+	if opts.Signal == 0 {
+		opts.Signal = syscall.SIGTERM
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultStopTimeout
+	}
+
+	jw.mutex.Lock()
+	switch jw.state {
+	case StatePending:
+		// the job never ran so nothing else will close its channels
+		err := jw.transition(StateStopped)
+		jw.mutex.Unlock()
+		jw.save()
+		jw.closeStdin()
+		jw.output.Close()
+		close(jw.done)
+		return err
+	case StateRunning, StatePaused:
+		// a frozen job cannot handle the signal, so it is thawed first
+		if jw.state == StatePaused {
+			if err := jw.cgroup.FreezeJobGroup(jw.cgroupPath, false); err != nil {
+				log.Printf("job %s: %v", jw.JobID, err)
+			}
+		}
+		jw.transition(StateStopping)
+	default:
+		state := jw.state
+		jw.mutex.Unlock()
+		return fmt.Errorf("job %s is %s", jw.JobID, state)
+	}
+	pid := jw.pid
+	jw.mutex.Unlock()
+	jw.save()
+
+	// a job that has not started its process yet has nothing to shut down gracefully
+	if pid == 0 {
+		jw.cancelJob()
+		<-jw.done
+		return nil
+	}
+	if err := signalGroup(pid, opts.Signal); err != nil {
+		log.Printf("job %s: %v", jw.JobID, err)
+	}
+
+	timer := time.NewTimer(opts.Timeout)
+	defer timer.Stop()
+	select {
+	case <-jw.done:
+	case <-timer.C:
+		jw.cancelJob()
+		<-jw.done
+	}
+	return nil
 }
 
-// todo rename to query
-func (jw *JobInfo) Status() string {
-	return jw.status
+// Signal sends a signal to every process of a running job
+func (jw *JobInfo) Signal(sig syscall.Signal) error {
+	jw.mutex.Lock()
+	state := jw.state
+	pid := jw.pid
+	jw.mutex.Unlock()
+
+	if state != StateRunning && state != StatePaused && state != StateStopping {
+		return fmt.Errorf("job %s is %s", jw.JobID, state)
+	}
+	if pid == 0 {
+		return fmt.Errorf("job %s has not started its process yet", jw.JobID)
+	}
+	return signalGroup(pid, sig)
 }
 
-func (jw *JobInfo) IsRunning() bool {
-	if jw.status == RunningStatus {
-		return true
+// Pause freezes every process of a running job through its cgroup
+func (jw *JobInfo) Pause() error {
+	defer jw.save()
+	jw.mutex.Lock()
+	defer jw.mutex.Unlock()
+	if jw.state != StateRunning {
+		return fmt.Errorf("job %s is %s", jw.JobID, jw.state)
+	}
+	if jw.cgroupPath == "" {
+		return fmt.Errorf("job %s has no cgroup to freeze", jw.JobID)
+	}
+	if err := jw.cgroup.FreezeJobGroup(jw.cgroupPath, true); err != nil {
+		return err
 	}
-	return false
+	return jw.transition(StatePaused)
 }
 
-func (jw *JobInfo) GetLog() <-chan string {
-	fmt.Println("Get Log")
-	outChan := make(chan string)
-	reader := bufio.NewReader(jw.output)
-	for {
-		line, err := reader.ReadString('\n')
-		if err != nil {
-			fmt.Println("returning out chan")
-			close(outChan)
-			return outChan
-		}
-		outChan <- line
+// Resume thaws a paused job
+func (jw *JobInfo) Resume() error {
+	defer jw.save()
+	jw.mutex.Lock()
+	defer jw.mutex.Unlock()
+	if jw.state != StatePaused {
+		return fmt.Errorf("job %s is %s", jw.JobID, jw.state)
 	}
+	if err := jw.cgroup.FreezeJobGroup(jw.cgroupPath, false); err != nil {
+		return err
+	}
+	return jw.transition(StateRunning)
+}
+
+// State returns the current state of the job
+func (jw *JobInfo) State() JobState {
+	jw.mutex.Lock()
+	defer jw.mutex.Unlock()
+	return jw.state
+}
+
+// History returns every state the job has been in, oldest first
+func (jw *JobInfo) History() []StateChange {
+	jw.mutex.Lock()
+	defer jw.mutex.Unlock()
+	history := make([]StateChange, len(jw.history))
+	copy(history, jw.history)
+	return history
+}
+
+// Result returns how the job ended, Reason is empty while it is running
+func (jw *JobInfo) Result() JobResult {
+	jw.mutex.Lock()
+	defer jw.mutex.Unlock()
+	return jw.result
+}
+
+// Username returns the user that owns the job
+func (jw *JobInfo) Username() string {
+	return jw.username
+}
+
+// Command returns the command line the job runs
+func (jw *JobInfo) Command() []string {
+	command := make([]string, len(jw.command))
+	copy(command, jw.command)
+	return command
+}
+
+// CreateTime returns when the job was created
+func (jw *JobInfo) CreateTime() time.Time {
+	jw.mutex.Lock()
+	defer jw.mutex.Unlock()
+	return jw.history[0].Time
 }
 
-func (jw *JobInfo) GetOutputChannel() <-chan string {
-	fmt.Println("sending output log")
-	return jw.outputChan
+// Done returns a channel closed once the job has ended and its result is final
+func (jw *JobInfo) Done() <-chan struct{} {
+	return jw.done
+}
+
+func (jw *JobInfo) IsRunning() bool {
+	return jw.State() == StateRunning
+}
+
+// GetLog returns the output the job has written so far
+func (jw *JobInfo) GetLog() []OutputChunk {
+	return jw.output.Chunks()
+}
+
+// Subscribe returns a channel with the job's output from the beginning followed
+// by live output, any number of subscribers can follow the same job.
+// The channel is closed once the job's output ends or ctx is done
+func (jw *JobInfo) Subscribe(ctx context.Context) <-chan OutputChunk {
+	return jw.output.Subscribe(ctx)
+}
+
+// Logs returns a channel with the part of the job's output selected by opts.
+// The channel is closed once the selected output has been sent or ctx is done
+func (jw *JobInfo) Logs(ctx context.Context, opts LogOptions) (<-chan OutputChunk, error) {
+	if opts.Offset < 0 || opts.Tail < 0 {
+		return nil, fmt.Errorf("offset and tail cannot be negative")
+	}
+
+	offset := opts.Offset
+	if opts.Tail > 0 {
+		offset = jw.output.TailOffset(opts.Tail)
+	}
+	return jw.output.SubscribeFrom(ctx, offset, opts.Follow), nil
 }
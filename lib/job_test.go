@@ -1,7 +1,14 @@
 package jobworker
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -10,54 +17,54 @@ import (
 
 func TestStartJob(t *testing.T) {
 	fmt.Println("starting job")
-	newJob, err := NewJob([]string{"ls"})
+	newJob, err := NewJob([]string{"ls"}, JobOptions{})
 	assert.Nil(t, err, "error creating new job")
 
 	go func() {
 		newJob.Start()
 	}()
 
-	for out := range newJob.outputChan {
+	for out := range newJob.Subscribe(context.Background()) {
 		fmt.Println(out)
 	}
 }
 
 func TestStartJobWithArgument(t *testing.T) {
 	fmt.Println("starting job")
-	newJob, err := NewJob([]string{"ls", "-al"})
+	newJob, err := NewJob([]string{"ls", "-al"}, JobOptions{})
 	assert.Nil(t, err, "error creating new job")
 
 	go func() {
 		newJob.Start()
 	}()
 
-	for out := range newJob.outputChan {
+	for out := range newJob.Subscribe(context.Background()) {
 		fmt.Println(out)
 	}
 }
 
 func TestLongRunningJobWithStop(t *testing.T) {
-	newJob, err := NewJob([]string{"ping", "127.0.0.1"})
+	newJob, err := NewJob([]string{"sh", "-c", "while true; do echo ping; sleep 0.5; done"}, JobOptions{})
 	assert.Nil(t, err, "error creating new job")
 	go func() {
 		newJob.Start()
 	}()
 	time.Sleep(1 * time.Second)
-	assert.Equal(t, "running", newJob.Status())
+	assert.Equal(t, StateRunning, newJob.State())
 
 	go func() {
-		for out := range newJob.outputChan {
+		for out := range newJob.Subscribe(context.Background()) {
 			fmt.Println(out)
 		}
 	}()
 
 	time.Sleep(3 * time.Second)
-	newJob.Stop()
-	assert.Equal(t, "stopped", newJob.Status())
+	assert.Nil(t, newJob.Stop(StopOptions{}), "error stopping job")
+	assert.Equal(t, StateStopped, newJob.State())
 }
 
 func TestQueryJob(t *testing.T) {
-	newJob, err := NewJob([]string{"ping", "127.0.0.1"})
+	newJob, err := NewJob([]string{"sh", "-c", "while true; do echo ping; sleep 0.5; done"}, JobOptions{})
 	assert.Nil(t, err, "error creating new job")
 
 	go func() {
@@ -66,13 +73,410 @@ func TestQueryJob(t *testing.T) {
 
 	time.Sleep(1 * time.Second)
 
-	assert.Equal(t, "running", newJob.Status())
+	assert.Equal(t, StateRunning, newJob.State())
 	go func() {
-		for out := range newJob.outputChan {
+		for out := range newJob.Subscribe(context.Background()) {
 			fmt.Println(out)
 		}
 	}()
-	newJob.Stop()
-	assert.Equal(t, "stopped", newJob.Status())
+	assert.Nil(t, newJob.Stop(StopOptions{}), "error stopping job")
+	assert.Equal(t, StateStopped, newJob.State())
 
 }
+
+// runJob helper func to run a job to completion and wait for all of its output
+func runJob(t *testing.T, command []string, opts JobOptions) *JobInfo {
+	newJob, err := NewJob(command, opts)
+	assert.Nil(t, err, "error creating new job")
+
+	newJob.Start()
+	for range newJob.Subscribe(context.Background()) {
+	}
+	return newJob
+}
+
+func TestJobExitCode(t *testing.T) {
+	newJob := runJob(t, []string{"sh", "-c", "exit 2"}, JobOptions{})
+
+	assert.Equal(t, StateFailed, newJob.State())
+	result := newJob.Result()
+	assert.Equal(t, 2, result.ExitCode)
+	assert.Equal(t, ReasonExited, result.Reason)
+	assert.Equal(t, "", result.Signal)
+	assert.False(t, result.StartTime.IsZero())
+	assert.False(t, result.EndTime.Before(result.StartTime))
+}
+
+func TestJobSignaled(t *testing.T) {
+	newJob := runJob(t, []string{"sh", "-c", "kill -TERM $$"}, JobOptions{})
+
+	assert.Equal(t, StateKilled, newJob.State())
+	result := newJob.Result()
+	assert.Equal(t, -1, result.ExitCode)
+	assert.Equal(t, ReasonSignaled, result.Reason)
+	assert.Equal(t, "SIGTERM", result.Signal)
+}
+
+func TestJobTimeout(t *testing.T) {
+	newJob := runJob(t, []string{"sleep", "10"}, JobOptions{Timeout: 100 * time.Millisecond})
+
+	result := newJob.Result()
+	assert.Equal(t, ReasonTimedOut, result.Reason)
+	assert.Equal(t, "SIGKILL", result.Signal)
+	assert.True(t, result.EndTime.Sub(result.StartTime) < 5*time.Second)
+}
+
+func TestJobStartFailed(t *testing.T) {
+	newJob, err := NewJob([]string{"/does/not/exist"}, JobOptions{})
+	assert.Nil(t, err, "error creating new job")
+	assert.Equal(t, ReasonNone, newJob.Result().Reason)
+
+	newJob.Start()
+	assert.Equal(t, StateFailed, newJob.State())
+	result := newJob.Result()
+	assert.Equal(t, ReasonStartFailed, result.Reason)
+	assert.Equal(t, -1, result.ExitCode)
+}
+
+func TestJobSucceededHistory(t *testing.T) {
+	newJob := runJob(t, []string{"true"}, JobOptions{})
+
+	assert.Equal(t, StateSucceeded, newJob.State())
+	history := newJob.History()
+	assert.Equal(t, 3, len(history))
+	assert.Equal(t, StatePending, history[0].State)
+	assert.Equal(t, StateRunning, history[1].State)
+	assert.Equal(t, StateSucceeded, history[2].State)
+}
+
+func TestStopJobHistory(t *testing.T) {
+	newJob, err := NewJob([]string{"sleep", "10"}, JobOptions{})
+	assert.Nil(t, err, "error creating new job")
+	go newJob.Start()
+
+	assert.Eventually(t, newJob.IsRunning, time.Second, 10*time.Millisecond)
+	assert.Nil(t, newJob.Stop(StopOptions{}), "error stopping job")
+	assert.Equal(t, ReasonStopped, newJob.Result().Reason)
+
+	var states []JobState
+	for _, change := range newJob.History() {
+		states = append(states, change.State)
+	}
+	assert.Equal(t, []JobState{StatePending, StateRunning, StateStopping, StateStopped}, states)
+
+	assert.NotNil(t, newJob.Stop(StopOptions{}), "expected error stopping a stopped job")
+}
+
+// startAndWait helper func to start a job and wait for its first output
+func startAndWait(t *testing.T, command []string) (*JobInfo, <-chan OutputChunk) {
+	newJob, err := NewJob(command, JobOptions{})
+	assert.Nil(t, err, "error creating new job")
+	output := newJob.Subscribe(context.Background())
+	go newJob.Start()
+	<-output
+	return newJob, output
+}
+
+// processGone helper func to check a process has exited, zombies included
+func processGone(pid int) bool {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return true
+	}
+	fields := strings.Fields(string(data[bytes.LastIndexByte(data, ')')+1:]))
+	return len(fields) > 0 && fields[0] == "Z"
+}
+
+func TestStopProcessGroup(t *testing.T) {
+	// the background sleep would outlive the shell if only the shell was signaled
+	newJob, err := NewJob([]string{"sh", "-c", "sleep 300 & echo $!; wait"}, JobOptions{})
+	assert.Nil(t, err, "error creating new job")
+	output := newJob.Subscribe(context.Background())
+	go newJob.Start()
+	chunk := <-output
+	pid, err := strconv.Atoi(strings.TrimSpace(string(chunk.Data)))
+	assert.Nil(t, err, "expected the pid of the background process")
+
+	assert.Nil(t, newJob.Stop(StopOptions{}), "error stopping job")
+	assert.Equal(t, StateStopped, newJob.State())
+	assert.Eventually(t, func() bool { return processGone(pid) }, time.Second, 10*time.Millisecond)
+}
+
+func TestJobExitKillsProcessGroup(t *testing.T) {
+	// the background sleep does not hold the output, only the job's lifecycle ends it
+	newJob := runJob(t, []string{"sh", "-c", "sleep 300 >/dev/null 2>&1 & echo $!"}, JobOptions{})
+	assert.Equal(t, StateSucceeded, newJob.State())
+	pid, err := strconv.Atoi(strings.TrimSpace(string(readFrom(newJob.output, 0))))
+	assert.Nil(t, err, "expected the pid of the background process")
+	assert.Eventually(t, func() bool { return processGone(pid) }, time.Second, 10*time.Millisecond)
+}
+
+func TestStopGracePeriod(t *testing.T) {
+	// everything in the job ignores SIGTERM so it has to be killed after the timeout
+	newJob, _ := startAndWait(t, []string{"sh", "-c", "trap '' TERM; echo ready; (sleep 300) & while true; do sleep 0.1; done"})
+
+	start := time.Now()
+	assert.Nil(t, newJob.Stop(StopOptions{Timeout: 500 * time.Millisecond}), "error stopping job")
+	elapsed := time.Since(start)
+	assert.True(t, elapsed >= 500*time.Millisecond, "job was killed before the timeout")
+	assert.True(t, elapsed < 5*time.Second, "job was not killed after the timeout")
+	assert.Equal(t, StateStopped, newJob.State())
+	assert.Equal(t, "SIGKILL", newJob.Result().Signal)
+}
+
+func TestStopSignal(t *testing.T) {
+	newJob, output := startAndWait(t, []string{"sh", "-c", "trap 'echo got usr1; exit 0' USR1; echo ready; while true; do sleep 0.1; done"})
+
+	assert.Nil(t, newJob.Stop(StopOptions{Signal: syscall.SIGUSR1}), "error stopping job")
+	var out []byte
+	for chunk := range output {
+		out = append(out, chunk.Data...)
+	}
+	assert.Contains(t, string(out), "got usr1")
+	assert.Equal(t, StateStopped, newJob.State())
+	assert.Equal(t, 0, newJob.Result().ExitCode)
+
+	assert.NotNil(t, newJob.Stop(StopOptions{Timeout: -time.Second}))
+	assert.NotNil(t, newJob.Stop(StopOptions{Timeout: MaxStopTimeout + time.Second}))
+}
+
+func TestJobSignal(t *testing.T) {
+	newJob, output := startAndWait(t, []string{"sh", "-c", "trap 'echo got hup' HUP; echo ready; while true; do sleep 0.1; done"})
+
+	assert.Nil(t, newJob.Signal(syscall.SIGHUP), "error sending signal")
+	// the whole group gets the signal, so the shell may first report the sleep it interrupted
+	var out []byte
+	for !bytes.Contains(out, []byte("got hup\n")) {
+		chunk, ok := <-output
+		if !assert.True(t, ok, "output closed before the trap ran") {
+			return
+		}
+		out = append(out, chunk.Data...)
+	}
+	assert.Equal(t, StateRunning, newJob.State())
+
+	assert.Nil(t, newJob.Stop(StopOptions{}), "error stopping job")
+	assert.NotNil(t, newJob.Signal(syscall.SIGHUP), "expected error signaling a stopped job")
+
+	pending, err := NewJob([]string{"true"}, JobOptions{})
+	assert.Nil(t, err, "error creating new job")
+	assert.NotNil(t, pending.Signal(syscall.SIGHUP), "expected error signaling a pending job")
+}
+
+func TestPauseResume(t *testing.T) {
+	newJob, _ := startAndWait(t, []string{"sh", "-c", "echo ready; while true; do sleep 0.1; done"})
+	assert.NotNil(t, newJob.Pause(), "expected error pausing a job without a cgroup")
+
+	// real jobs cannot be cloned into a fake cgroupfs, so point the running job at one
+	_, cgroup := fakeCGroup(t)
+	path, err := cgroup.CreateJobGroup("alice", newJob.JobID, DefaultLimits())
+	assert.Nil(t, err, "error creating job cgroup")
+	newJob.mutex.Lock()
+	newJob.cgroup = cgroup
+	newJob.cgroupPath = path
+	newJob.mutex.Unlock()
+	freeze := filepath.Join(path, FreezeFile)
+
+	assert.NotNil(t, newJob.Resume(), "expected error resuming a running job")
+	assert.Nil(t, newJob.Pause(), "error pausing job")
+	assert.Equal(t, StatePaused, newJob.State())
+	assert.Equal(t, "1", readCGroupFile(t, freeze))
+	assert.NotNil(t, newJob.Pause(), "expected error pausing a paused job")
+
+	assert.Nil(t, newJob.Resume(), "error resuming job")
+	assert.Equal(t, StateRunning, newJob.State())
+	assert.Equal(t, "0", readCGroupFile(t, freeze))
+
+	// a paused job is thawed before it is stopped
+	assert.Nil(t, newJob.Pause(), "error pausing job")
+	assert.Nil(t, newJob.Stop(StopOptions{}), "error stopping paused job")
+	assert.Equal(t, "0", readCGroupFile(t, freeze))
+	assert.Equal(t, StateStopped, newJob.State())
+
+	var states []JobState
+	for _, change := range newJob.History() {
+		states = append(states, change.State)
+	}
+	assert.Equal(t, []JobState{StatePending, StateRunning, StatePaused, StateRunning, StatePaused, StateStopping, StateStopped}, states)
+}
+
+func TestJobStdin(t *testing.T) {
+	newJob, err := NewJob([]string{"sort"}, JobOptions{Stdin: true})
+	assert.Nil(t, err, "error creating new job")
+
+	// input written before the process starts waits in the pipe
+	assert.Nil(t, newJob.WriteStdin([]byte("pear\napple\n")), "error writing stdin")
+	output := newJob.Subscribe(context.Background())
+	go newJob.Start()
+	assert.Nil(t, newJob.WriteStdin([]byte("fig\n")), "error writing stdin")
+	assert.Nil(t, newJob.CloseStdin(), "error closing stdin")
+	assert.NotNil(t, newJob.WriteStdin([]byte("kiwi\n")), "expected error writing closed stdin")
+
+	var out []byte
+	for chunk := range output {
+		out = append(out, chunk.Data...)
+	}
+	assert.Equal(t, "apple\nfig\npear\n", string(out))
+	<-newJob.done
+	assert.Equal(t, StateSucceeded, newJob.State())
+}
+
+func TestJobWithoutStdin(t *testing.T) {
+	// without stdin the job reads /dev/null instead of waiting for input
+	newJob := runJob(t, []string{"cat"}, JobOptions{})
+	assert.Equal(t, StateSucceeded, newJob.State())
+	assert.NotNil(t, newJob.WriteStdin([]byte("hi\n")), "expected error writing stdin of a job without stdin")
+	assert.NotNil(t, newJob.CloseStdin(), "expected error closing stdin of a job without stdin")
+}
+
+// readUntil helper func to read output until it contains want
+func readUntil(t *testing.T, output <-chan OutputChunk, want string) string {
+	var out []byte
+	for !bytes.Contains(out, []byte(want)) {
+		chunk, ok := <-output
+		if !assert.True(t, ok, "output closed before %q", want) {
+			break
+		}
+		out = append(out, chunk.Data...)
+	}
+	return string(out)
+}
+
+func TestJobTTY(t *testing.T) {
+	command := []string{"sh", "-c", "test -t 0 && test -t 1 && test -t 2 && echo is a tty; stty size; read line; stty size; echo got $line; cat"}
+	newJob, err := NewJob(command, JobOptions{TTY: true, TerminalSize: TerminalSize{Rows: 24, Cols: 80}})
+	assert.Nil(t, err, "error creating new job")
+	assert.True(t, newJob.TTY())
+	output := newJob.Subscribe(context.Background())
+	go newJob.Start()
+
+	// a terminal translates newlines to CRLF
+	out := readUntil(t, output, "24 80\r\n")
+	assert.Contains(t, out, "is a tty\r\n")
+
+	assert.Nil(t, newJob.Resize(TerminalSize{Rows: 40, Cols: 120}), "error resizing terminal")
+	assert.Nil(t, newJob.WriteStdin([]byte("hello\n")), "error writing stdin")
+	out = readUntil(t, output, "got hello\r\n")
+	assert.Contains(t, out, "40 120\r\n")
+
+	// ctrl-D ends cat's input without closing the terminal
+	assert.Nil(t, newJob.CloseStdin(), "error closing stdin")
+	<-newJob.Done()
+	assert.Equal(t, StateSucceeded, newJob.State())
+	assert.NotNil(t, newJob.Resize(TerminalSize{Rows: 24, Cols: 80}), "expected error resizing a closed terminal")
+
+	notTTY, err := NewJob([]string{"true"}, JobOptions{Stdin: true})
+	assert.Nil(t, err, "error creating new job")
+	assert.NotNil(t, notTTY.Resize(TerminalSize{Rows: 24, Cols: 80}), "expected error resizing a job without a terminal")
+	notTTY.Stop(StopOptions{})
+}
+
+func TestStopPendingJob(t *testing.T) {
+	newJob, err := NewJob([]string{"sleep", "10"}, JobOptions{})
+	assert.Nil(t, err, "error creating new job")
+
+	assert.Nil(t, newJob.Stop(StopOptions{}), "error stopping pending job")
+	assert.Equal(t, StateStopped, newJob.State())
+
+	// a stopped job never runs
+	newJob.Start()
+	assert.Equal(t, StateStopped, newJob.State())
+	_, ok := <-newJob.Subscribe(context.Background())
+	assert.False(t, ok, "expected output channel to be closed")
+}
+
+func TestJobStderrOutput(t *testing.T) {
+	newJob, err := NewJob([]string{"sh", "-c", "echo out; echo err >&2"}, JobOptions{})
+	assert.Nil(t, err, "error creating new job")
+	go newJob.Start()
+
+	var chunks []OutputChunk
+	for out := range newJob.Subscribe(context.Background()) {
+		chunks = append(chunks, out)
+	}
+
+	streams := make(map[string]Stream)
+	for _, chunk := range chunks {
+		streams[string(chunk.Data)] = chunk.Stream
+		assert.False(t, chunk.Time.IsZero(), "expected chunk to have a timestamp")
+	}
+	assert.Equal(t, map[string]Stream{"out\n": Stdout, "err\n": Stderr}, streams)
+
+	assert.Equal(t, chunks, newJob.GetLog())
+}
+
+func TestJobBinaryOutput(t *testing.T) {
+	// a line longer than a scanner buffer, carriage returns and bytes that are not utf-8
+	script := "head -c 100000 /dev/zero | tr '\\0' a; printf '10%%\\r50%%\\r100%%\\r'; printf '\\377\\000\\376'"
+	newJob, err := NewJob([]string{"sh", "-c", script}, JobOptions{})
+	assert.Nil(t, err, "error creating new job")
+	go newJob.Start()
+
+	var out []byte
+	for chunk := range newJob.Subscribe(context.Background()) {
+		assert.Equal(t, Stdout, chunk.Stream)
+		assert.True(t, len(chunk.Data) <= ChunkSize)
+		out = append(out, chunk.Data...)
+	}
+
+	expected := append(bytes.Repeat([]byte("a"), 100000), []byte("10%\r50%\r100%\r\xff\x00\xfe")...)
+	assert.Equal(t, expected, out)
+}
+
+func TestJobMultipleSubscribers(t *testing.T) {
+	newJob, err := NewJob([]string{"sh", "-c", "echo one; sleep 0.2; echo two"}, JobOptions{})
+	assert.Nil(t, err, "error creating new job")
+
+	// a subscriber that never reads must not block the job
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	newJob.Subscribe(ctx)
+
+	early := newJob.Subscribe(context.Background())
+	newJob.Start()
+	assert.Equal(t, StateSucceeded, newJob.State())
+
+	// subscribers get the full output no matter when they attach
+	late := newJob.Subscribe(context.Background())
+	for _, sub := range []<-chan OutputChunk{early, late} {
+		var out []byte
+		for chunk := range sub {
+			out = append(out, chunk.Data...)
+		}
+		assert.Equal(t, "one\ntwo\n", string(out))
+	}
+}
+
+func TestJobLogs(t *testing.T) {
+	newJob := runJob(t, []string{"sh", "-c", "echo one; echo two; echo three"}, JobOptions{})
+
+	readLogs := func(opts LogOptions) string {
+		logs, err := newJob.Logs(context.Background(), opts)
+		assert.Nil(t, err, "error reading logs")
+		var out []byte
+		for chunk := range logs {
+			out = append(out, chunk.Data...)
+		}
+		return string(out)
+	}
+	assert.Equal(t, "one\ntwo\nthree\n", readLogs(LogOptions{}))
+	assert.Equal(t, "two\nthree\n", readLogs(LogOptions{Offset: 4}))
+	assert.Equal(t, "three\n", readLogs(LogOptions{Tail: 1, Follow: true}))
+
+	_, err := newJob.Logs(context.Background(), LogOptions{Offset: -1})
+	assert.NotNil(t, err, "expected error for negative offset")
+}
+
+func TestJobLogsFollow(t *testing.T) {
+	newJob, err := NewJob([]string{"sh", "-c", "echo one; sleep 0.2; echo two"}, JobOptions{})
+	assert.Nil(t, err, "error creating new job")
+	go newJob.Start()
+
+	logs, err := newJob.Logs(context.Background(), LogOptions{Follow: true})
+	assert.Nil(t, err, "error reading logs")
+	var out []byte
+	for chunk := range logs {
+		out = append(out, chunk.Data...)
+	}
+	assert.Equal(t, "one\ntwo\n", string(out))
+}
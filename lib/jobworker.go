@@ -4,20 +4,104 @@ package jobworker
 
 import (
 	"fmt"
+	"log"
+	"sort"
+	"strings"
 	"sync"
+	"time"
+)
+
+const (
+	// DefaultPageSize is the number of jobs listed when no page size is given
+	DefaultPageSize = 50
+	// MaxPageSize is the most jobs listed at once
+	MaxPageSize = 500
 )
 
 type JobWorker struct {
 	mutex    sync.Mutex
 	userJobs map[string][]*JobInfo
+	store    JobStore
+}
+
+// ListFilter selects the jobs returned by ListJobs, unset fields match every job
+// States: only jobs in one of these states
+// Command: only jobs whose command line contains this string
+// Since: only jobs created at or after this time
+// Until: only jobs created before this time
+type ListFilter struct {
+	States  []JobState
+	Command string
+	Since   time.Time
+	Until   time.Time
+}
+
+// Retention sets which jobs that have ended are kept, zero fields keep them all.
+// Jobs that are still active are always kept
+// MaxAge: jobs that ended longer ago than this are dropped
+// MaxJobs: most ended jobs kept, the ones that ended last
+type Retention struct {
+	MaxAge  time.Duration
+	MaxJobs int
 }
 
+// Validate checks the retention is well formed
+func (r Retention) Validate() error {
+	if r.MaxAge < 0 {
+		return fmt.Errorf("retention age cannot be negative")
+	}
+	if r.MaxJobs < 0 {
+		return fmt.Errorf("retained jobs cannot be negative")
+	}
+	return nil
+}
+
+// NewJobWorker returns a worker keeping its jobs in memory only
 func NewJobWorker() *JobWorker {
 	return &JobWorker{userJobs: make(map[string][]*JobInfo)}
 }
 
+// NewJobWorkerWithStore returns a worker persisting its jobs to store, with the
+// jobs already saved in it. Jobs that were active when the store was last used
+// are lost, and whatever they left running in their cgroup under cgroup is
+// killed. cgroup is nil when jobs are not placed in cgroups
+func NewJobWorkerWithStore(store JobStore, cgroup *CGroup) (*JobWorker, error) {
+	stored, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	jw := &JobWorker{userJobs: make(map[string][]*JobInfo), store: store}
+	records := make([]JobRecord, 0, len(stored))
+	var lost []*JobInfo
+	for _, s := range stored {
+		job := restoreJob(s, store)
+		jw.userJobs[s.Username] = append(jw.userJobs[s.Username], job)
+		if !s.State.IsFinal() {
+			lost = append(lost, job)
+		}
+		job.mutex.Lock()
+		records = append(records, job.record())
+		job.mutex.Unlock()
+	}
+	if cgroup != nil {
+		reapLostJobs(cgroup, lost)
+	}
+	// the log only keeps growing otherwise, and is replayed on every restart
+	if err := store.Compact(records); err != nil {
+		return nil, err
+	}
+	return jw, nil
+}
+
+// AddJob adds a new job of the user, saved to the worker's store first. The
+// job is not added when it cannot be saved
 func (jw *JobWorker) AddJob(username string, job *JobInfo) error {
-	fmt.Printf("adding user %s job to map\n", username)
+	if jw.store != nil {
+		if err := job.setStore(jw.store); err != nil {
+			return err
+		}
+	}
 	jw.mutex.Lock()
 	defer jw.mutex.Unlock()
 	jobs, ok := jw.userJobs[username]
@@ -28,19 +112,190 @@ func (jw *JobWorker) AddJob(username string, job *JobInfo) error {
 
 	jobs = append(jobs, job)
 	jw.userJobs[username] = jobs
-	fmt.Printf("total map length is %d\n", len(jw.userJobs))
-
 	return nil
 }
 
-func (jw *JobWorker) FindJob(username string, jobID string) (*JobInfo, error) {
-	fmt.Printf("total map length is %d\n", len(jw.userJobs))
-	jobs := jw.userJobs[username]
-	fmt.Printf("user job length is %d\n", len(jobs))
-	for _, j := range jobs {
-		if j.JobID == jobID {
-			return j, nil
+// Prune drops the jobs that ended and retention does not keep, from the worker,
+// its store and the output folder, and returns how many were dropped
+func (jw *JobWorker) Prune(retention Retention) (int, error) {
+	now := time.Now()
+	jw.mutex.Lock()
+	var ended []*JobInfo
+	for _, jobs := range jw.userJobs {
+		for _, j := range jobs {
+			// done is closed once the job's last change was saved
+			select {
+			case <-j.done:
+				ended = append(ended, j)
+			default:
+			}
+		}
+	}
+	endTimes := make(map[*JobInfo]time.Time, len(ended))
+	for _, j := range ended {
+		endTime := j.Result().EndTime
+		if endTime.IsZero() {
+			endTime = j.CreateTime()
+		}
+		endTimes[j] = endTime
+	}
+	sort.Slice(ended, func(a, b int) bool {
+		return endTimes[ended[a]].After(endTimes[ended[b]])
+	})
+
+	dropped := make(map[*JobInfo]bool)
+	for i, j := range ended {
+		tooMany := retention.MaxJobs > 0 && i >= retention.MaxJobs
+		tooOld := retention.MaxAge > 0 && now.Sub(endTimes[j]) > retention.MaxAge
+		if tooMany || tooOld {
+			dropped[j] = true
+		}
+	}
+	if len(dropped) == 0 {
+		jw.mutex.Unlock()
+		return 0, nil
+	}
+	for username, jobs := range jw.userJobs {
+		kept := make([]*JobInfo, 0, len(jobs))
+		for _, j := range jobs {
+			if !dropped[j] {
+				kept = append(kept, j)
+			}
+		}
+		if len(kept) == 0 {
+			delete(jw.userJobs, username)
+			continue
+		}
+		jw.userJobs[username] = kept
+	}
+	jw.mutex.Unlock()
+
+	jobIDs := make([]string, 0, len(dropped))
+	for j := range dropped {
+		jobIDs = append(jobIDs, j.JobID)
+	}
+	if jw.store != nil {
+		// the records go first, a crash then leaves spool files nothing refers to
+		// instead of jobs without their output
+		if err := jw.store.Remove(jobIDs); err != nil {
+			return 0, err
+		}
+	}
+	for j := range dropped {
+		j.output.Close()
+		if err := removeOutput(j.JobID, j.outputOpts); err != nil {
+			log.Printf("job %s: %v", j.JobID, err)
+		}
+	}
+	return len(dropped), nil
+}
+
+// LookupJob returns the job with the id whoever owns it, callers check the
+// caller is allowed to see it
+func (jw *JobWorker) LookupJob(jobID string) (*JobInfo, error) {
+	jw.mutex.Lock()
+	defer jw.mutex.Unlock()
+	for _, jobs := range jw.userJobs {
+		for _, j := range jobs {
+			if j.JobID == jobID {
+				return j, nil
+			}
 		}
 	}
 	return nil, fmt.Errorf("cannot find a job with id %s", jobID)
 }
+
+// ListJobs returns a page of the user's jobs matching the filter, oldest first.
+// The page starts after the job named by pageToken, the returned token is empty
+// on the last page
+func (jw *JobWorker) ListJobs(username string, filter ListFilter, pageSize int, pageToken string) ([]*JobInfo, string, error) {
+	jw.mutex.Lock()
+	jobs := make([]*JobInfo, len(jw.userJobs[username]))
+	copy(jobs, jw.userJobs[username])
+	jw.mutex.Unlock()
+	return listPage(jobs, filter, pageSize, pageToken)
+}
+
+// ListAllJobs returns a page of every user's jobs matching the filter, oldest
+// first, paged like ListJobs
+func (jw *JobWorker) ListAllJobs(filter ListFilter, pageSize int, pageToken string) ([]*JobInfo, string, error) {
+	var jobs []*JobInfo
+	jw.mutex.Lock()
+	for _, userJobs := range jw.userJobs {
+		jobs = append(jobs, userJobs...)
+	}
+	jw.mutex.Unlock()
+	sort.SliceStable(jobs, func(i, k int) bool {
+		return jobs[i].CreateTime().Before(jobs[k].CreateTime())
+	})
+	return listPage(jobs, filter, pageSize, pageToken)
+}
+
+// listPage() helper func to get the page of jobs matching the filter after pageToken
+func listPage(jobs []*JobInfo, filter ListFilter, pageSize int, pageToken string) ([]*JobInfo, string, error) {
+	if pageSize < 0 {
+		return nil, "", fmt.Errorf("page size cannot be negative")
+	}
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	start := 0
+	if pageToken != "" {
+		start = -1
+		for i, j := range jobs {
+			if j.JobID == pageToken {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			return nil, "", fmt.Errorf("invalid page token %q", pageToken)
+		}
+	}
+
+	var page []*JobInfo
+	for i := start; i < len(jobs); i++ {
+		if !filter.matches(jobs[i]) {
+			continue
+		}
+		if len(page) == pageSize {
+			// there is at least one more match, continue after the last job returned
+			return page, page[len(page)-1].JobID, nil
+		}
+		page = append(page, jobs[i])
+	}
+	return page, "", nil
+}
+
+// matches() helper func to check a job against the filter
+func (f ListFilter) matches(job *JobInfo) bool {
+	if len(f.States) > 0 {
+		state := job.State()
+		found := false
+		for _, s := range f.States {
+			if s == state {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Command != "" && !strings.Contains(strings.Join(job.Command(), " "), f.Command) {
+		return false
+	}
+
+	created := job.CreateTime()
+	if !f.Since.IsZero() && created.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !created.Before(f.Until) {
+		return false
+	}
+	return true
+}
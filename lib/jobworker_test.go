@@ -0,0 +1,113 @@
+package jobworker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func addJobs(t *testing.T, jw *JobWorker, username string, commands ...string) []*JobInfo {
+	var jobs []*JobInfo
+	for _, command := range commands {
+		job, err := NewJob([]string{"sh", "-c", command}, JobOptions{Username: username})
+		assert.Nil(t, err)
+		jw.AddJob(username, job)
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+func TestLookupJob(t *testing.T) {
+	jw := NewJobWorker()
+	jobs := addJobs(t, jw, "alice", "echo a")
+
+	job, err := jw.LookupJob(jobs[0].JobID)
+	assert.Nil(t, err)
+	assert.Equal(t, jobs[0], job)
+	assert.Equal(t, "alice", job.Username())
+	_, err = jw.LookupJob("missing")
+	assert.NotNil(t, err)
+}
+
+func TestListAllJobs(t *testing.T) {
+	jw := NewJobWorker()
+	alice := addJobs(t, jw, "alice", "echo a")
+	bob := addJobs(t, jw, "bob", "echo b")
+	alice = append(alice, addJobs(t, jw, "alice", "echo c")...)
+
+	listed, token, err := jw.ListAllJobs(ListFilter{}, 2, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []*JobInfo{alice[0], bob[0]}, listed)
+	listed, token, err = jw.ListAllJobs(ListFilter{}, 2, token)
+	assert.Nil(t, err)
+	assert.Equal(t, []*JobInfo{alice[1]}, listed)
+	assert.Equal(t, "", token)
+
+	listed, _, err = jw.ListAllJobs(ListFilter{Command: "echo b"}, 0, "")
+	assert.Nil(t, err)
+	assert.Equal(t, bob, listed)
+}
+
+func TestListJobsFilter(t *testing.T) {
+	jw := NewJobWorker()
+	jobs := addJobs(t, jw, "alice", "echo a", "sleep 1", "echo b")
+	addJobs(t, jw, "bob", "echo c")
+	assert.Nil(t, jobs[1].Stop(StopOptions{}))
+
+	listed, token, err := jw.ListJobs("alice", ListFilter{}, 0, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "", token)
+	assert.Equal(t, jobs, listed)
+
+	listed, _, err = jw.ListJobs("alice", ListFilter{States: []JobState{StateStopped}}, 0, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []*JobInfo{jobs[1]}, listed)
+
+	listed, _, err = jw.ListJobs("alice", ListFilter{Command: "echo"}, 0, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []*JobInfo{jobs[0], jobs[2]}, listed)
+
+	listed, _, err = jw.ListJobs("alice", ListFilter{Since: jobs[1].CreateTime(), Until: jobs[2].CreateTime()}, 0, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []*JobInfo{jobs[1]}, listed)
+
+	listed, _, err = jw.ListJobs("alice", ListFilter{Until: jobs[0].CreateTime().Add(-time.Second)}, 0, "")
+	assert.Nil(t, err)
+	assert.Empty(t, listed)
+
+	listed, _, err = jw.ListJobs("carl", ListFilter{}, 0, "")
+	assert.Nil(t, err)
+	assert.Empty(t, listed)
+}
+
+func TestListJobsPages(t *testing.T) {
+	jw := NewJobWorker()
+	jobs := addJobs(t, jw, "alice", "echo a", "echo b", "echo c", "echo d", "echo e")
+
+	listed, token, err := jw.ListJobs("alice", ListFilter{}, 2, "")
+	assert.Nil(t, err)
+	assert.Equal(t, jobs[:2], listed)
+	assert.NotEqual(t, "", token)
+
+	listed, token, err = jw.ListJobs("alice", ListFilter{}, 2, token)
+	assert.Nil(t, err)
+	assert.Equal(t, jobs[2:4], listed)
+	assert.NotEqual(t, "", token)
+
+	listed, token, err = jw.ListJobs("alice", ListFilter{}, 2, token)
+	assert.Nil(t, err)
+	assert.Equal(t, jobs[4:], listed)
+	assert.Equal(t, "", token)
+
+	// no token when the last page is exactly full
+	listed, token, err = jw.ListJobs("alice", ListFilter{}, 5, "")
+	assert.Nil(t, err)
+	assert.Equal(t, jobs, listed)
+	assert.Equal(t, "", token)
+
+	_, _, err = jw.ListJobs("alice", ListFilter{}, 2, "unknown")
+	assert.NotNil(t, err)
+	_, _, err = jw.ListJobs("alice", ListFilter{}, -1, "")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,312 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+const (
+	MinCPUPeriod = 1000
+	MaxCPUPeriod = 1000000
+)
+
+var ioDeviceRegex = regexp.MustCompile(`^[0-9]+:[0-9]+$`)
+
+// IOLimit represents the io.max limits of a single block device
+// Device: major:minor number of the device, i.e. 8:0 for /dev/sda
+// A zero value means no limit
+type IOLimit struct {
+	Device    string `json:"device"`
+	ReadBPS   int64  `json:"rbps,omitempty"`
+	WriteBPS  int64  `json:"wbps,omitempty"`
+	ReadIOPS  int64  `json:"riops,omitempty"`
+	WriteIOPS int64  `json:"wiops,omitempty"`
+}
+
+// Limits represents the resource limits written to a job's cgroup
+// CPUQuota: microseconds of cpu time allowed in each CPUPeriod
+// Memory: memory.max in bytes
+// Pids: maximum number of processes in the job
+// A zero value means no limit
+type Limits struct {
+	CPUQuota  int64     `json:"cpuQuota,omitempty"`
+	CPUPeriod int64     `json:"cpuPeriod,omitempty"`
+	Memory    int64     `json:"memory,omitempty"`
+	IO        []IOLimit `json:"io,omitempty"`
+	Pids      int64     `json:"pids,omitempty"`
+}
+
+// DefaultLimits returns the hardcoded limits used when a job does not ask for any.
+// Block devices differ between hosts so io is only limited by a LimitPolicy
+func DefaultLimits() Limits {
+	return Limits{
+		CPUQuota:  MaxCPU,
+		CPUPeriod: CPUPeriod,
+		Memory:    MaxMEM,
+	}
+}
+
+// Validate checks the limits are well formed
+func (l Limits) Validate() error {
+	if l.CPUQuota < 0 || l.Memory < 0 || l.Pids < 0 {
+		return fmt.Errorf("limits cannot be negative")
+	}
+	if l.CPUPeriod != 0 && (l.CPUPeriod < MinCPUPeriod || l.CPUPeriod > MaxCPUPeriod) {
+		return fmt.Errorf("cpu period must be between %d and %d", MinCPUPeriod, MaxCPUPeriod)
+	}
+	devices := make(map[string]bool)
+	for _, io := range l.IO {
+		if !ioDeviceRegex.MatchString(io.Device) {
+			return fmt.Errorf("invalid io device %q, expected major:minor", io.Device)
+		}
+		if devices[io.Device] {
+			return fmt.Errorf("io device %s listed more than once", io.Device)
+		}
+		devices[io.Device] = true
+		if io.ReadBPS < 0 || io.WriteBPS < 0 || io.ReadIOPS < 0 || io.WriteIOPS < 0 {
+			return fmt.Errorf("io limits cannot be negative")
+		}
+	}
+	return nil
+}
+
+// WithDefaults returns the limits with every unset field taken from defaults
+func (l Limits) WithDefaults(defaults Limits) Limits {
+	merged := l
+	if merged.CPUQuota == 0 {
+		merged.CPUQuota = defaults.CPUQuota
+	}
+	if merged.CPUPeriod == 0 {
+		merged.CPUPeriod = defaults.CPUPeriod
+	}
+	if merged.Memory == 0 {
+		merged.Memory = defaults.Memory
+	}
+	if merged.Pids == 0 {
+		merged.Pids = defaults.Pids
+	}
+
+	merged.IO = nil
+	for _, def := range defaults.IO {
+		io := def
+		if requested, ok := l.ioLimit(def.Device); ok {
+			io = requested.withDefaults(def)
+		}
+		merged.IO = append(merged.IO, io)
+	}
+	for _, io := range l.IO {
+		if _, ok := defaults.ioLimit(io.Device); !ok {
+			merged.IO = append(merged.IO, io)
+		}
+	}
+	return merged
+}
+
+// Exceeds returns an error describing the first limit that is above the ceiling.
+// Both limits are expected to have defaults applied, unset ceiling fields are not checked
+func (l Limits) Exceeds(ceiling Limits) error {
+	if ceiling.CPUQuota != 0 {
+		if l.CPUQuota == 0 || l.CPUPeriod == 0 || ceiling.CPUPeriod == 0 {
+			return fmt.Errorf("cpu must be limited to at most %d/%d", ceiling.CPUQuota, ceiling.CPUPeriod)
+		}
+		// compare the cpu share of both limits since their periods can differ
+		if l.CPUQuota*ceiling.CPUPeriod > ceiling.CPUQuota*l.CPUPeriod {
+			return fmt.Errorf("cpu %d/%d exceeds the allowed %d/%d", l.CPUQuota, l.CPUPeriod, ceiling.CPUQuota, ceiling.CPUPeriod)
+		}
+	}
+	if err := exceeds("memory", l.Memory, ceiling.Memory); err != nil {
+		return err
+	}
+	if err := exceeds("pids", l.Pids, ceiling.Pids); err != nil {
+		return err
+	}
+	for _, maxIO := range ceiling.IO {
+		io, _ := l.ioLimit(maxIO.Device)
+		checks := []struct {
+			name    string
+			value   int64
+			ceiling int64
+		}{
+			{"rbps", io.ReadBPS, maxIO.ReadBPS},
+			{"wbps", io.WriteBPS, maxIO.WriteBPS},
+			{"riops", io.ReadIOPS, maxIO.ReadIOPS},
+			{"wiops", io.WriteIOPS, maxIO.WriteIOPS},
+		}
+		for _, c := range checks {
+			if err := exceeds(fmt.Sprintf("io %s %s", maxIO.Device, c.name), c.value, c.ceiling); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cpuMax returns the contents of cpu.max
+func (l Limits) cpuMax() string {
+	period := l.CPUPeriod
+	if period == 0 {
+		period = CPUPeriod
+	}
+	return fmt.Sprintf("%s %d", limitValue(l.CPUQuota), period)
+}
+
+// ioMax returns the io.max lines, one per device
+func (l Limits) ioMax() []string {
+	var lines []string
+	for _, io := range l.IO {
+		lines = append(lines, fmt.Sprintf("%s rbps=%s wbps=%s riops=%s wiops=%s", io.Device,
+			limitValue(io.ReadBPS), limitValue(io.WriteBPS), limitValue(io.ReadIOPS), limitValue(io.WriteIOPS)))
+	}
+	return lines
+}
+
+func (l Limits) ioLimit(device string) (IOLimit, bool) {
+	for _, io := range l.IO {
+		if io.Device == device {
+			return io, true
+		}
+	}
+	return IOLimit{}, false
+}
+
+func (io IOLimit) withDefaults(defaults IOLimit) IOLimit {
+	if io.ReadBPS == 0 {
+		io.ReadBPS = defaults.ReadBPS
+	}
+	if io.WriteBPS == 0 {
+		io.WriteBPS = defaults.WriteBPS
+	}
+	if io.ReadIOPS == 0 {
+		io.ReadIOPS = defaults.ReadIOPS
+	}
+	if io.WriteIOPS == 0 {
+		io.WriteIOPS = defaults.WriteIOPS
+	}
+	return io
+}
+
+// LimitPolicy is the administrator defined policy for job limits
+// Default: limits applied to fields a job does not set
+// Ceiling: highest limits any user may request
+// Users: per user ceilings replacing Ceiling for that user
+type LimitPolicy struct {
+	Default Limits            `json:"default"`
+	Ceiling Limits            `json:"ceiling"`
+	Users   map[string]Limits `json:"users,omitempty"`
+}
+
+// DefaultLimitPolicy returns a policy where users can only lower the default limits
+func DefaultLimitPolicy() LimitPolicy {
+	return LimitPolicy{Default: DefaultLimits(), Ceiling: DefaultLimits()}
+}
+
+// Validate checks the default limits and every ceiling are well formed
+func (p LimitPolicy) Validate() error {
+	if err := p.Default.validatePolicy(); err != nil {
+		return fmt.Errorf("invalid default limits: %v", err)
+	}
+	if err := p.Ceiling.validatePolicy(); err != nil {
+		return fmt.Errorf("invalid ceiling: %v", err)
+	}
+	for _, username := range p.usernames() {
+		if err := p.Users[username].validatePolicy(); err != nil {
+			return fmt.Errorf("invalid ceiling of user %s: %v", username, err)
+		}
+	}
+	return nil
+}
+
+// Resolve applies the policy defaults to the requested limits and checks
+// the result against the user's ceiling
+func (p LimitPolicy) Resolve(username string, requested Limits) (Limits, error) {
+	if err := requested.Validate(); err != nil {
+		return Limits{}, err
+	}
+
+	ceiling, ok := p.Users[username]
+	if !ok {
+		ceiling = p.Ceiling
+	}
+
+	limits := requested.WithDefaults(p.Default)
+	if err := limits.Exceeds(ceiling); err != nil {
+		return Limits{}, fmt.Errorf("%s is not allowed: %v", username, err)
+	}
+	return limits, nil
+}
+
+// CheckDevices returns an error for the first io device of the policy that is
+// not a block device of the host, listed in dir as major:minor. dir is normally
+// BlockDevicesFolder
+func (p LimitPolicy) CheckDevices(dir string) error {
+	check := func(name string, limits Limits) error {
+		for _, io := range limits.IO {
+			if _, err := os.Stat(filepath.Join(dir, io.Device)); err != nil {
+				return fmt.Errorf("%s: io device %s is not a block device of this host", name, io.Device)
+			}
+		}
+		return nil
+	}
+
+	if err := check("default", p.Default); err != nil {
+		return err
+	}
+	if err := check("ceiling", p.Ceiling); err != nil {
+		return err
+	}
+	for _, username := range p.usernames() {
+		if err := check("user "+username, p.Users[username]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// usernames() helper func to list the users of the policy in order
+func (p LimitPolicy) usernames() []string {
+	usernames := make([]string, 0, len(p.Users))
+	for username := range p.Users {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+	return usernames
+}
+
+// validatePolicy() helper func to check limits of a policy, a cpu quota is only
+// comparable with the period it is given in
+func (l Limits) validatePolicy() error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	if l.CPUQuota != 0 && l.CPUPeriod == 0 {
+		return fmt.Errorf("cpu quota %d needs a cpu period", l.CPUQuota)
+	}
+	return nil
+}
+
+// exceeds helper func to compare a limit with its ceiling, zero means unlimited
+func exceeds(name string, value int64, ceiling int64) error {
+	if ceiling == 0 {
+		return nil
+	}
+	if value == 0 {
+		return fmt.Errorf("%s must be limited to at most %d", name, ceiling)
+	}
+	if value > ceiling {
+		return fmt.Errorf("%s %d exceeds the allowed %d", name, value, ceiling)
+	}
+	return nil
+}
+
+// limitValue helper func to format a cgroup limit, zero means unlimited
+func limitValue(value int64) string {
+	if value == 0 {
+		return "max"
+	}
+	return fmt.Sprintf("%d", value)
+}
@@ -0,0 +1,115 @@
+package jobworker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLimits(t *testing.T) {
+	assert.Nil(t, Limits{}.Validate())
+	assert.Nil(t, DefaultLimits().Validate())
+
+	invalid := []Limits{
+		{Memory: -1},
+		{CPUPeriod: 10},
+		{CPUPeriod: 2000000},
+		{IO: []IOLimit{{Device: "sda"}}},
+		{IO: []IOLimit{{Device: "8:0"}, {Device: "8:0"}}},
+		{IO: []IOLimit{{Device: "8:0", WriteBPS: -5}}},
+	}
+	for _, l := range invalid {
+		assert.NotNil(t, l.Validate(), "expected %+v to be invalid", l)
+	}
+}
+
+func TestLimitsWithDefaults(t *testing.T) {
+	requested := Limits{
+		Memory: 1024,
+		IO: []IOLimit{
+			{Device: "8:0", WriteBPS: 512},
+			{Device: "8:16", ReadBPS: 256},
+		},
+	}
+	defaults := DefaultLimits()
+	defaults.IO = []IOLimit{{Device: "8:0", WriteBPS: 1048576, WriteIOPS: 120}}
+	limits := requested.WithDefaults(defaults)
+
+	assert.Equal(t, int64(MaxCPU), limits.CPUQuota)
+	assert.Equal(t, int64(CPUPeriod), limits.CPUPeriod)
+	assert.Equal(t, int64(1024), limits.Memory)
+	assert.Equal(t, []IOLimit{
+		{Device: "8:0", WriteBPS: 512, WriteIOPS: 120},
+		{Device: "8:16", ReadBPS: 256},
+	}, limits.IO)
+}
+
+func TestLimitPolicyResolve(t *testing.T) {
+	policy := DefaultLimitPolicy()
+	policy.Default.IO = []IOLimit{{Device: "8:0", WriteBPS: 1048576}}
+	policy.Ceiling.IO = []IOLimit{{Device: "8:0", WriteBPS: 1048576}}
+	policy.Users = map[string]Limits{
+		"bob": {CPUQuota: 200000, CPUPeriod: 200000, Memory: 2 * MaxMEM},
+	}
+
+	limits, err := policy.Resolve("alice", Limits{})
+	assert.Nil(t, err, "default limits should be allowed")
+	assert.Equal(t, policy.Default, limits)
+
+	_, err = policy.Resolve("alice", Limits{CPUQuota: 50000, CPUPeriod: 100000, Memory: 1024})
+	assert.Nil(t, err, "lower limits should be allowed")
+
+	_, err = policy.Resolve("alice", Limits{Memory: 2 * MaxMEM})
+	assert.NotNil(t, err, "memory above the ceiling should not be allowed")
+
+	// same share of the cpu with a different period is allowed, a larger share is not
+	_, err = policy.Resolve("alice", Limits{CPUQuota: 50000, CPUPeriod: 100000})
+	assert.Nil(t, err, "cpu share at the ceiling should be allowed")
+	_, err = policy.Resolve("alice", Limits{CPUQuota: 60000, CPUPeriod: 100000})
+	assert.NotNil(t, err, "cpu share above the ceiling should not be allowed")
+
+	_, err = policy.Resolve("alice", Limits{IO: []IOLimit{{Device: "8:0", WriteBPS: 2097152}}})
+	assert.NotNil(t, err, "io above the ceiling should not be allowed")
+	_, err = policy.Resolve("alice", Limits{IO: []IOLimit{{Device: "8:0", WriteBPS: 524288}}})
+	assert.Nil(t, err, "io below the ceiling should be allowed")
+
+	_, err = policy.Resolve("bob", Limits{CPUQuota: 200000, Memory: 2 * MaxMEM})
+	assert.Nil(t, err, "user ceiling should replace the global ceiling")
+
+	_, err = policy.Resolve("alice", Limits{Memory: -1})
+	assert.NotNil(t, err, "invalid limits should not be allowed")
+}
+
+func TestLimitPolicyValidate(t *testing.T) {
+	assert.Nil(t, DefaultLimitPolicy().Validate())
+
+	invalid := []LimitPolicy{
+		{Default: Limits{Memory: -1}},
+		{Default: Limits{CPUQuota: 100000}},
+		{Ceiling: Limits{CPUQuota: 100000}},
+		{Ceiling: Limits{Pids: -1}},
+		{Users: map[string]Limits{"bob": {CPUQuota: 100000}}},
+		{Users: map[string]Limits{"bob": {IO: []IOLimit{{Device: "8:0", ReadBPS: -1}}}}},
+	}
+	for _, p := range invalid {
+		assert.NotNil(t, p.Validate(), "expected %+v to be invalid", p)
+	}
+}
+
+func TestLimitPolicyCheckDevices(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "253:0"), nil, 0644))
+
+	assert.Nil(t, DefaultLimitPolicy().CheckDevices(dir), "default policy should not limit any device")
+
+	policy := DefaultLimitPolicy()
+	policy.Ceiling.IO = []IOLimit{{Device: "253:0", WriteBPS: 1048576}}
+	assert.Nil(t, policy.CheckDevices(dir))
+
+	policy.Users = map[string]Limits{"bob": {IO: []IOLimit{{Device: "8:0", WriteBPS: 1048576}}}}
+	err := policy.CheckDevices(dir)
+	assert.NotNil(t, err, "expected error for a missing device")
+	assert.Contains(t, err.Error(), "user bob")
+}
@@ -0,0 +1,140 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"fmt"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Namespaces selects the new namespaces a job runs in, the host's are shared otherwise
+// PID: the job only sees its own processes, with a private /proc. Implies Mount
+// Mount: mounts made by the job are private to it
+// UTS: the job gets its own hostname, its job id
+// IPC: the job gets its own System V IPC objects and POSIX message queues
+// Net: the job gets its own network stack with only a loopback device
+type Namespaces struct {
+	PID   bool `json:"pid,omitempty"`
+	Mount bool `json:"mount,omitempty"`
+	UTS   bool `json:"uts,omitempty"`
+	IPC   bool `json:"ipc,omitempty"`
+	Net   bool `json:"net,omitempty"`
+}
+
+// AllNamespaces returns every namespace a job can be isolated with
+func AllNamespaces() Namespaces {
+	return Namespaces{PID: true, Mount: true, UTS: true, IPC: true, Net: true}
+}
+
+// Any returns whether at least one new namespace is selected
+func (n Namespaces) Any() bool {
+	return n.PID || n.Mount || n.UTS || n.IPC || n.Net
+}
+
+// Union returns the namespaces selected in either n or other
+func (n Namespaces) Union(other Namespaces) Namespaces {
+	return Namespaces{
+		PID:   n.PID || other.PID,
+		Mount: n.Mount || other.Mount || n.PID || other.PID,
+		UTS:   n.UTS || other.UTS,
+		IPC:   n.IPC || other.IPC,
+		Net:   n.Net || other.Net,
+	}
+}
+
+// cloneflags() helper func to convert the namespaces to clone flags
+func (n Namespaces) cloneflags() uintptr {
+	var flags uintptr
+	if n.PID {
+		flags |= syscall.CLONE_NEWPID
+	}
+	if n.Mount || n.PID {
+		flags |= syscall.CLONE_NEWNS
+	}
+	if n.UTS {
+		flags |= syscall.CLONE_NEWUTS
+	}
+	if n.IPC {
+		flags |= syscall.CLONE_NEWIPC
+	}
+	if n.Net {
+		flags |= syscall.CLONE_NEWNET
+	}
+	return flags
+}
+
+// setup() helper func run by the child process inside the new namespaces to
+// finish setting them up before the command runs, rootfs becomes the root
+// filesystem when not nil
+func (n Namespaces) setup(hostname string, rootfs *rootfsLayer) error {
+	if n.Mount || n.PID || rootfs != nil {
+		// without this mounts could still propagate to the host's namespace
+		if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+			return fmt.Errorf("failed to make mounts private: %v", err)
+		}
+	}
+	if rootfs != nil {
+		if err := rootfs.pivot(); err != nil {
+			return err
+		}
+	}
+	if n.PID || rootfs != nil {
+		// the host's /proc shows the host's processes, the new one only the job's.
+		// A root filesystem starts with an empty /proc
+		if err := unix.Mount("proc", "/proc", "proc", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, ""); err != nil {
+			return fmt.Errorf("failed to mount /proc: %v", err)
+		}
+	}
+	if n.UTS && hostname != "" {
+		if err := unix.Sethostname([]byte(hostname)); err != nil {
+			return fmt.Errorf("failed to set hostname: %v", err)
+		}
+	}
+	if n.Net {
+		if err := loopbackUp(); err != nil {
+			return fmt.Errorf("failed to bring up loopback: %v", err)
+		}
+	}
+	return nil
+}
+
+// loopbackUp() helper func to bring up the loopback device of a new network
+// namespace, it starts down so even localhost would be unreachable
+func loopbackUp() error {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+
+	ifreq, err := unix.NewIfreq("lo")
+	if err != nil {
+		return err
+	}
+	if err := unix.IoctlIfreq(fd, unix.SIOCGIFFLAGS, ifreq); err != nil {
+		return err
+	}
+	ifreq.SetUint16(ifreq.Uint16() | unix.IFF_UP)
+	return unix.IoctlIfreq(fd, unix.SIOCSIFFLAGS, ifreq)
+}
+
+// NamespacePolicy is the administrator defined minimum isolation of jobs,
+// jobs can always ask for more namespaces than required
+// Default: namespaces every job runs in
+// Users: per user namespaces replacing Default for that user
+type NamespacePolicy struct {
+	Default Namespaces            `json:"default"`
+	Users   map[string]Namespaces `json:"users,omitempty"`
+}
+
+// Resolve returns the namespaces a user's job runs in, the requested ones
+// plus the ones the policy requires
+func (p NamespacePolicy) Resolve(username string, requested Namespaces) Namespaces {
+	required, ok := p.Users[username]
+	if !ok {
+		required = p.Default
+	}
+	return requested.Union(required)
+}
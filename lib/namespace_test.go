@@ -0,0 +1,89 @@
+package jobworker
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespacePolicyResolve(t *testing.T) {
+	policy := NamespacePolicy{
+		Default: Namespaces{Net: true},
+		Users:   map[string]Namespaces{"carl": {PID: true}},
+	}
+
+	assert.Equal(t, Namespaces{Net: true}, policy.Resolve("alice", Namespaces{}))
+	assert.Equal(t, Namespaces{Net: true, UTS: true}, policy.Resolve("alice", Namespaces{UTS: true}))
+	// carl's policy replaces the default, and pid brings its own mount namespace
+	assert.Equal(t, Namespaces{PID: true, Mount: true}, policy.Resolve("carl", Namespaces{}))
+	assert.Equal(t, AllNamespaces(), policy.Resolve("bob", AllNamespaces()))
+	assert.False(t, Namespaces{}.Any())
+}
+
+// isolatedJob helper func to run a command in every namespace
+func isolatedJob(t *testing.T, command []string) *JobInfo {
+	if os.Geteuid() != 0 {
+		t.Skip("creating namespaces needs root")
+	}
+	return runJob(t, command, JobOptions{Process: ProcessOptions{Namespaces: AllNamespaces()}})
+}
+
+func TestJobNamespaces(t *testing.T) {
+	// the private /proc hides the host's processes and pid 1 is the child process,
+	// the only network device is the loopback
+	script := fmt.Sprintf("test -e /proc/%d || echo hidden; tr '\\0' ' ' < /proc/1/cmdline | cut -d' ' -f1; hostname; grep -c : /proc/net/dev", os.Getpid())
+	newJob := isolatedJob(t, []string{"sh", "-c", script})
+	assert.Equal(t, StateSucceeded, newJob.State())
+	assert.Equal(t, "hidden\n"+childName+"\n"+newJob.JobID+"\n1\n", string(readFrom(newJob.output, 0)))
+
+	newJob = isolatedJob(t, []string{"sh", "-c", "exit 3"})
+	assert.Equal(t, 3, newJob.Result().ExitCode)
+
+	// a command killed by a signal ends the job like it does without a pid namespace
+	newJob = isolatedJob(t, []string{"sh", "-c", "kill -9 $$"})
+	assert.Equal(t, StateKilled, newJob.State())
+	result := newJob.Result()
+	assert.Equal(t, ReasonSignaled, result.Reason)
+	assert.Equal(t, "SIGKILL", result.Signal)
+	assert.Equal(t, -1, result.ExitCode)
+
+	// whatever the command leaves behind is killed with the namespace
+	start := time.Now()
+	newJob = isolatedJob(t, []string{"sh", "-c", "sleep 30 & echo started"})
+	assert.Equal(t, StateSucceeded, newJob.State())
+	assert.True(t, time.Since(start) < 10*time.Second)
+}
+
+func TestStopIsolatedJob(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating namespaces needs root")
+	}
+	newJob, err := NewJob([]string{"sh", "-c", "echo ready; sleep 30"}, JobOptions{Process: ProcessOptions{Namespaces: AllNamespaces()}})
+	assert.Nil(t, err, "error creating new job")
+	go newJob.Start()
+	for len(newJob.GetLog()) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the command gets the signal even though pid 1 ignores it
+	start := time.Now()
+	assert.Nil(t, newJob.Stop(StopOptions{Timeout: 10 * time.Second}), "error stopping job")
+	assert.Equal(t, StateStopped, newJob.State())
+	assert.True(t, time.Since(start) < 5*time.Second, "job was killed instead of stopped")
+}
+
+func TestIsolatedJobCredential(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating namespaces needs root")
+	}
+	process := ProcessOptions{
+		Credential: &Credential{UID: nobody, GID: nobody},
+		Namespaces: AllNamespaces(),
+	}
+	script := fmt.Sprintf("id -u; test -e /proc/%d || echo hidden", os.Getpid())
+	newJob := runJob(t, []string{"sh", "-c", script}, JobOptions{Process: process})
+	assert.Equal(t, "65534\nhidden\n", string(readFrom(newJob.output, 0)))
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ChunkSize is the most output read from the process at once
+const ChunkSize = 32 * 1024
+
+// Stream identifies which output of the process a chunk came from
+type Stream int
+
+const (
+	Stdout Stream = iota
+	Stderr
+)
+
+func (s Stream) String() string {
+	switch s {
+	case Stdout:
+		return "stdout"
+	case Stderr:
+		return "stderr"
+	}
+	return fmt.Sprintf("unknown(%d)", int(s))
+}
+
+// OutputChunk is a piece of a job's output
+// Stream: stdout or stderr
+// Time: when the job wrote the chunk
+// Data: raw bytes as written by the job, not split on lines
+// Offset: position of the chunk's first byte in the job's output
+type OutputChunk struct {
+	Stream Stream
+	Time   time.Time
+	Data   []byte
+	Offset int64
+}
+
+// LogOptions selects the part of a job's output to read
+// Offset: byte offset in the output to start from
+// Tail: start from the last Tail lines instead of Offset when set
+// Follow: keep sending live output until the job's output ends
+type LogOptions struct {
+	Offset int64
+	Tail   int
+	Follow bool
+}
+
+// captureOutput() helper func to create the pipes the command writes its stdout
+// and stderr to. Both are read until EOF and the output is closed afterwards,
+// so the returned writers must be closed once the command has started
+func (j *JobInfo) captureOutput() (*os.File, *os.File, error) {
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create stdout pipe: %v", err)
+	}
+	stderrReader, stderrWriter, err := os.Pipe()
+	if err != nil {
+		stdoutReader.Close()
+		stdoutWriter.Close()
+		return nil, nil, fmt.Errorf("failed to create stderr pipe: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go j.readOutput(Stdout, stdoutReader, &wg)
+	go j.readOutput(Stderr, stderrReader, &wg)
+	go j.closeOutput(&wg)
+
+	return stdoutWriter, stderrWriter, nil
+}
+
+// closeOutput() helper func to close the output once every reader is done
+func (j *JobInfo) closeOutput(wg *sync.WaitGroup) {
+	wg.Wait()
+	if state := j.State(); state == StateStopping || state == StateStopped {
+		j.output.Write(OutputChunk{Stream: Stdout, Time: time.Now(), Data: []byte("Job has been stopped by user\n")})
+	}
+	j.output.Close()
+}
+
+// readOutput() helper func to store and send everything written to a stream
+func (j *JobInfo) readOutput(stream Stream, r io.ReadCloser, wg *sync.WaitGroup) {
+	defer wg.Done()
+	defer r.Close()
+
+	buf := make([]byte, ChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			j.output.Write(OutputChunk{Stream: stream, Time: time.Now(), Data: data})
+		}
+		if err != nil {
+			// a terminal's master fails with EIO instead of EOF once the slave is closed
+			if err != io.EOF && !errors.Is(err, syscall.EIO) {
+				log.Printf("job %s: failed to read %s: %v", j.JobID, stream, err)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// MaxUmask is the largest file mode creation mask, every permission bit masked
+const MaxUmask = 0777
+
+// ProcessOptions sets up the environment a job's command runs in
+// Env: KEY=VALUE variables added to the server's environment, later ones win
+// CleanEnv: start from an empty environment instead of the server's
+// Dir: absolute path of the working directory, the server's when empty
+// Umask: file mode creation mask, the server's when nil
+// Credential: local user the processes run as, the server's when nil
+// Namespaces: new namespaces isolating the job from the host
+// RootFS: root filesystem the job runs in, the host's when nil. Dir and the
+// command are then looked up inside it, and it implies a mount namespace
+// Seccomp: system calls the job is not allowed to make, unfiltered when empty
+// Path: absolute path of the executable to run, inside RootFS when set. The
+// command's first argument is looked up when empty, and stays its argv[0]
+// either way. Set to the path a CommandPolicy checked, so the job runs that
+// file and not whatever the command names by the time it starts
+type ProcessOptions struct {
+	Env        []string
+	CleanEnv   bool
+	Dir        string
+	Umask      *uint32
+	Credential *Credential
+	Namespaces Namespaces
+	RootFS     *RootFS
+	Seccomp    SeccompProfile
+	Path       string
+}
+
+// Validate checks the process options are well formed
+func (p ProcessOptions) Validate() error {
+	for _, variable := range p.Env {
+		key := strings.SplitN(variable, "=", 2)[0]
+		if key == variable {
+			return fmt.Errorf("invalid environment variable %q, expected KEY=VALUE", variable)
+		}
+		if key == "" {
+			return fmt.Errorf("environment variable %q has no name", variable)
+		}
+		if strings.ContainsRune(variable, 0) {
+			return fmt.Errorf("environment variable %s cannot contain a NUL byte", key)
+		}
+	}
+	if p.Dir != "" && !filepath.IsAbs(p.Dir) {
+		return fmt.Errorf("working directory %q must be an absolute path", p.Dir)
+	}
+	if strings.ContainsRune(p.Dir, 0) {
+		return fmt.Errorf("working directory cannot contain a NUL byte")
+	}
+	if p.Path != "" && !filepath.IsAbs(p.Path) {
+		return fmt.Errorf("executable %q must be an absolute path", p.Path)
+	}
+	if strings.ContainsRune(p.Path, 0) {
+		return fmt.Errorf("executable cannot contain a NUL byte")
+	}
+	if p.Umask != nil && *p.Umask > MaxUmask {
+		return fmt.Errorf("umask %o is larger than %o", *p.Umask, MaxUmask)
+	}
+	if err := p.Seccomp.Validate(); err != nil {
+		return err
+	}
+	if p.RootFS != nil {
+		if err := p.RootFS.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.Credential != nil {
+		return p.Credential.Validate()
+	}
+	return nil
+}
+
+// environ() helper func to build the environment of the job's command
+func (p ProcessOptions) environ() []string {
+	if p.CleanEnv {
+		// an empty but non-nil Env keeps exec from using the server's environment
+		return append([]string{}, p.Env...)
+	}
+	return append(os.Environ(), p.Env...)
+}
+
+// needsChild() helper func to check if the command has to be started through
+// the child process because exec.Cmd cannot apply every option itself
+func (p ProcessOptions) needsChild() bool {
+	return p.Umask != nil || p.Namespaces.Any() || p.RootFS != nil || p.Seccomp != SeccompNone
+}
+
+// apply() helper func to apply the options to the command of job jobID before
+// it starts, cmd.SysProcAttr must be set already. layer is the job's root
+// filesystem layer when it has one. With a pid namespace it returns the pipe
+// the command's signal is reported on, see readSignal
+func (p ProcessOptions) apply(cmd *exec.Cmd, jobID string, layer *rootfsLayer) (*os.File, error) {
+	cmd.Env = p.environ()
+	cmd.Dir = p.Dir
+	cmd.SysProcAttr.Cloneflags |= p.Namespaces.cloneflags()
+	if p.Path != "" {
+		cmd.Path = p.Path
+		cmd.Err = nil
+	}
+	if layer != nil {
+		// the command and working directory are found in the new root by the child
+		// process, whatever the host has at those paths does not matter
+		cmd.SysProcAttr.Cloneflags |= syscall.CLONE_NEWNS
+		if p.Path == "" {
+			cmd.Path = cmd.Args[0]
+		}
+		cmd.Dir = ""
+		cmd.Err = nil
+	}
+	// the command was not found, let Start report it
+	if !p.needsChild() || cmd.Err != nil {
+		if p.Credential != nil {
+			cmd.SysProcAttr.Credential = p.Credential.sysCredential()
+		}
+		return nil, nil
+	}
+	config := childConfig{
+		Umask:      p.Umask,
+		Namespaces: p.Namespaces,
+		Hostname:   jobID,
+		Credential: p.Credential,
+		RootFS:     layer,
+		Dir:        p.Dir,
+		Seccomp:    p.Seccomp,
+	}
+	var signals *os.File
+	if p.Namespaces.PID {
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create signal pipe: %v", err)
+		}
+		cmd.ExtraFiles = append(cmd.ExtraFiles, writer)
+		// the extra files follow stdin, stdout and stderr
+		config.SignalFD = 2 + len(cmd.ExtraFiles)
+		signals = reader
+	}
+	if err := wrapChild(cmd, config); err != nil {
+		if signals != nil {
+			signals.Close()
+		}
+		return nil, err
+	}
+	return signals, nil
+}
@@ -0,0 +1,81 @@
+package jobworker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMain lets the test binary run jobs through the child process like the server does
+func TestMain(m *testing.M) {
+	RunChild()
+	os.Exit(m.Run())
+}
+
+func umask(mask uint32) *uint32 {
+	return &mask
+}
+
+func TestProcessOptionsValidate(t *testing.T) {
+	assert.Nil(t, ProcessOptions{}.Validate())
+	assert.Nil(t, ProcessOptions{Env: []string{"A=1", "B=", "C=x=y"}, Dir: "/tmp", Umask: umask(0077)}.Validate())
+	assert.NotNil(t, ProcessOptions{Env: []string{"A"}}.Validate())
+	assert.NotNil(t, ProcessOptions{Env: []string{"=1"}}.Validate())
+	assert.NotNil(t, ProcessOptions{Env: []string{"A=\x00"}}.Validate())
+	assert.NotNil(t, ProcessOptions{Dir: "tmp"}.Validate())
+	assert.NotNil(t, ProcessOptions{Umask: umask(01000)}.Validate())
+	assert.Nil(t, ProcessOptions{Path: "/bin/sh"}.Validate())
+	assert.NotNil(t, ProcessOptions{Path: "sh"}.Validate())
+}
+
+func TestJobPath(t *testing.T) {
+	// the path is run whatever the command is called, which stays its argv[0]
+	newJob := runJob(t, []string{"missing-command", "-c", "echo ran"}, JobOptions{Process: ProcessOptions{Path: "/bin/sh"}})
+	assert.Equal(t, StateSucceeded, newJob.State())
+	assert.Equal(t, "ran\n", string(newJob.GetLog()[0].Data))
+
+	newJob = runJob(t, []string{"missing-command", "-c", "echo ran"}, JobOptions{Process: ProcessOptions{Path: "/bin/sh", Umask: umask(0022)}})
+	assert.Equal(t, StateSucceeded, newJob.State())
+	assert.Equal(t, "ran\n", string(newJob.GetLog()[0].Data))
+}
+
+func TestJobEnv(t *testing.T) {
+	os.Setenv("JOBWORKER_TEST_SERVER", "server")
+	defer os.Unsetenv("JOBWORKER_TEST_SERVER")
+	command := []string{"sh", "-c", "echo $JOBWORKER_TEST_SERVER,$A,$B"}
+
+	newJob := runJob(t, command, JobOptions{Process: ProcessOptions{Env: []string{"A=1", "B=2", "A=3"}}})
+	assert.Equal(t, "server,3,2\n", string(newJob.GetLog()[0].Data))
+
+	newJob = runJob(t, command, JobOptions{Process: ProcessOptions{Env: []string{"A=1"}, CleanEnv: true}})
+	assert.Equal(t, ",1,\n", string(newJob.GetLog()[0].Data))
+}
+
+func TestJobDir(t *testing.T) {
+	dir := t.TempDir()
+	newJob := runJob(t, []string{"pwd"}, JobOptions{Process: ProcessOptions{Dir: dir}})
+	assert.Equal(t, dir+"\n", string(newJob.GetLog()[0].Data))
+
+	newJob = runJob(t, []string{"pwd"}, JobOptions{Process: ProcessOptions{Dir: filepath.Join(dir, "missing")}})
+	assert.Equal(t, StateFailed, newJob.State())
+	assert.Equal(t, ReasonStartFailed, newJob.Result().Reason)
+}
+
+func TestJobUmask(t *testing.T) {
+	dir := t.TempDir()
+	newJob := runJob(t, []string{"sh", "-c", "umask; touch file"}, JobOptions{Process: ProcessOptions{Dir: dir, Umask: umask(0027)}})
+	assert.Equal(t, StateSucceeded, newJob.State())
+	assert.Equal(t, "0027\n", string(newJob.GetLog()[0].Data))
+	info, err := os.Stat(filepath.Join(dir, "file"))
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	// the child reports a command it cannot run on the job's stderr
+	newJob = runJob(t, []string{"/proc/self/missing"}, JobOptions{Process: ProcessOptions{Umask: umask(0)}})
+	assert.Equal(t, StateFailed, newJob.State())
+	assert.Equal(t, childFailedCode, newJob.Result().ExitCode)
+	assert.True(t, strings.HasPrefix(string(newJob.GetLog()[0].Data), "jobworker: failed to run"))
+}
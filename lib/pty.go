@@ -0,0 +1,147 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// eotChar is what a terminal in canonical mode reads as EOF, ctrl-D
+const eotChar = 0x04
+
+// TerminalSize is the size of a job's terminal in characters, zero leaves it unchanged
+type TerminalSize struct {
+	Rows uint16
+	Cols uint16
+}
+
+// openPTY() helper func to create the pseudo-terminal of a job started with a tty.
+// The slave end takes the place of the stdin pipe's read end and becomes the
+// process's stdin, stdout, stderr and controlling terminal. The master end takes
+// the place of the write end, so input is written like any other stdin
+func (j *JobInfo) openPTY(size TerminalSize) error {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open pseudo-terminal: %v", err)
+	}
+
+	var number int
+	err = control(master, func(fd int) error {
+		if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+			return err
+		}
+		number, err = unix.IoctlGetInt(fd, unix.TIOCGPTN)
+		return err
+	})
+	if err != nil {
+		master.Close()
+		return fmt.Errorf("failed to unlock pseudo-terminal: %v", err)
+	}
+	slave, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", number), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return fmt.Errorf("failed to open pseudo-terminal: %v", err)
+	}
+
+	j.stdinReader = slave
+	j.stdinWriter = master
+	if err := setSize(master, size); err != nil {
+		j.closeStdin()
+		return err
+	}
+	return nil
+}
+
+// captureTTY() helper func to read everything the job writes to its terminal into
+// its output, the terminal's counterpart of captureOutput. The master is duplicated
+// so the output is read to the end even when the job's master is closed first. The
+// returned stdout and stderr are the same copy of the slave, closing it twice is harmless
+func (j *JobInfo) captureTTY() (*os.File, *os.File, error) {
+	j.mutex.Lock()
+	master := j.stdinWriter
+	slave := j.stdinReader
+	j.mutex.Unlock()
+
+	reader, err := dupFile(master)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read pseudo-terminal: %v", err)
+	}
+	terminal, err := dupFile(slave)
+	if err != nil {
+		reader.Close()
+		return nil, nil, fmt.Errorf("failed to open pseudo-terminal: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go j.readOutput(Stdout, reader, &wg)
+	go j.closeOutput(&wg)
+
+	return terminal, terminal, nil
+}
+
+// dupFile() helper func to duplicate a file's descriptor
+func dupFile(file *os.File) (*os.File, error) {
+	var dup int
+	err := control(file, func(fd int) error {
+		var err error
+		dup, err = unix.FcntlInt(uintptr(fd), unix.F_DUPFD_CLOEXEC, 0)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return os.NewFile(uintptr(dup), file.Name()), nil
+}
+
+// control() helper func to run fn on a file's descriptor without switching the
+// file to blocking mode the way Fd() does
+func control(file *os.File, fn func(fd int) error) error {
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var fnErr error
+	if err := conn.Control(func(fd uintptr) { fnErr = fn(int(fd)) }); err != nil {
+		return err
+	}
+	return fnErr
+}
+
+// setSize() helper func to set the size of a terminal through its master
+func setSize(master *os.File, size TerminalSize) error {
+	if size.Rows == 0 || size.Cols == 0 {
+		return nil
+	}
+	err := control(master, func(fd int) error {
+		return unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, &unix.Winsize{Row: size.Rows, Col: size.Cols})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resize terminal: %v", err)
+	}
+	return nil
+}
+
+// TTY returns whether the job runs in a pseudo-terminal
+func (jw *JobInfo) TTY() bool {
+	return jw.tty
+}
+
+// Resize changes the size of the job's terminal, the job's foreground
+// processes get SIGWINCH from the kernel
+func (jw *JobInfo) Resize(size TerminalSize) error {
+	jw.mutex.Lock()
+	defer jw.mutex.Unlock()
+	if !jw.tty {
+		return fmt.Errorf("job %s was not started with a terminal", jw.JobID)
+	}
+	if jw.stdinWriter == nil {
+		return fmt.Errorf("terminal of job %s is closed", jw.JobID)
+	}
+	return setSize(jw.stdinWriter, size)
+}
@@ -0,0 +1,201 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// defaultPath is where commands are looked up in a root filesystem when the job has no PATH
+const defaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// rootfsDevices are the host devices bind mounted into the /dev of a root filesystem
+var rootfsDevices = []string{"null", "zero", "full", "random", "urandom", "tty"}
+
+// imageName is what image names look like, they cannot start with a dot or leave the images folder
+var imageName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Images is the server managed folder of root filesystem images, every
+// subdirectory is an extracted image named after it
+// Dir: absolute path of the images folder
+// LayerDir: absolute path of the folder the jobs' writable layers are created in
+type Images struct {
+	Dir      string
+	LayerDir string
+}
+
+// Setup creates the layer folder and removes the layers left by jobs of a
+// previous run. Whatever those jobs left running has to be killed first, see
+// NewJobWorkerWithStore, or their overlays would lose their layers under them
+func (i Images) Setup() error {
+	if !filepath.IsAbs(i.Dir) || !filepath.IsAbs(i.LayerDir) {
+		return fmt.Errorf("images and layer folders must be absolute paths")
+	}
+	if err := os.RemoveAll(i.LayerDir); err != nil {
+		return fmt.Errorf("failed to remove old layers: %v", err)
+	}
+	if err := os.MkdirAll(i.LayerDir, 0700); err != nil {
+		return fmt.Errorf("failed to create layer folder: %v", err)
+	}
+	return nil
+}
+
+// RootFS returns the root filesystem of the image called name
+func (i Images) RootFS(name string) (*RootFS, error) {
+	if !imageName.MatchString(name) {
+		return nil, fmt.Errorf("invalid image name %q", name)
+	}
+	rootfs := &RootFS{Image: filepath.Join(i.Dir, name), Dir: i.LayerDir}
+	if err := rootfs.Validate(); err != nil {
+		return nil, fmt.Errorf("unknown image %q", name)
+	}
+	return rootfs, nil
+}
+
+// RootFS runs a job in a root filesystem of its own instead of the host's.
+// The image is the lower layer of an overlay, so the job's writes go to a
+// layer of its own and the image is never modified
+// Image: absolute path of the directory holding the extracted image
+// Dir: absolute path of the folder the job's layer is created in
+type RootFS struct {
+	Image string
+	Dir   string
+}
+
+// Validate checks the image exists
+func (r RootFS) Validate() error {
+	if !filepath.IsAbs(r.Image) || !filepath.IsAbs(r.Dir) {
+		return fmt.Errorf("root filesystem image and layer folder must be absolute paths")
+	}
+	info, err := os.Stat(r.Image)
+	if err != nil {
+		return fmt.Errorf("failed to find root filesystem image: %v", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("root filesystem image %s is not a directory", r.Image)
+	}
+	return nil
+}
+
+// rootfsLayer holds the directories of a job's overlay
+// Image: read only lower layer
+// Upper: the job's writes
+// Work: overlayfs scratch space, on the same filesystem as Upper
+// Merged: mount point of the overlay, the job's new root
+type rootfsLayer struct {
+	Image  string `json:"image"`
+	Upper  string `json:"upper"`
+	Work   string `json:"work"`
+	Merged string `json:"merged"`
+}
+
+// layerDir() helper func to get the folder of a job's layer
+func (r RootFS) layerDir(jobID string) string {
+	return filepath.Join(r.Dir, jobID)
+}
+
+// createLayer() helper func to create the directories of a job's layer. The
+// top of the upper layer becomes the job's /, so it gets the image's owner and mode
+func (r RootFS) createLayer(jobID string) (rootfsLayer, error) {
+	dir := r.layerDir(jobID)
+	layer := rootfsLayer{
+		Image:  r.Image,
+		Upper:  filepath.Join(dir, "upper"),
+		Work:   filepath.Join(dir, "work"),
+		Merged: filepath.Join(dir, "merged"),
+	}
+	info, err := os.Stat(r.Image)
+	if err != nil {
+		return layer, fmt.Errorf("failed to find root filesystem image: %v", err)
+	}
+	for _, path := range []string{layer.Upper, layer.Work, layer.Merged} {
+		if err := os.MkdirAll(path, 0700); err != nil {
+			return layer, fmt.Errorf("failed to create root filesystem layer: %v", err)
+		}
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		if err := os.Chown(layer.Upper, int(stat.Uid), int(stat.Gid)); err != nil {
+			return layer, fmt.Errorf("failed to create root filesystem layer: %v", err)
+		}
+	}
+	if err := os.Chmod(layer.Upper, info.Mode().Perm()); err != nil {
+		return layer, fmt.Errorf("failed to create root filesystem layer: %v", err)
+	}
+	return layer, nil
+}
+
+// removeLayer() helper func to remove a job's layer once its processes are gone.
+// The overlay itself was mounted in the job's mount namespace and went with it
+func (r RootFS) removeLayer(jobID string) error {
+	if err := os.RemoveAll(r.layerDir(jobID)); err != nil {
+		return fmt.Errorf("failed to remove root filesystem layer: %v", err)
+	}
+	return nil
+}
+
+// pivot() helper func run by the child process in the job's mount namespace to
+// make the overlay its root. The host's root is detached afterwards so nothing
+// outside the image can be reached
+func (l rootfsLayer) pivot() error {
+	options := fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s", l.Image, l.Upper, l.Work)
+	if err := unix.Mount("overlay", l.Merged, "overlay", 0, options); err != nil {
+		return fmt.Errorf("failed to mount root filesystem: %v", err)
+	}
+
+	// images rarely come with device nodes, so /dev is a tmpfs with the harmless ones
+	dev := filepath.Join(l.Merged, "dev")
+	if err := os.MkdirAll(dev, 0755); err != nil {
+		return fmt.Errorf("failed to create /dev: %v", err)
+	}
+	if err := unix.Mount("tmpfs", dev, "tmpfs", unix.MS_NOSUID|unix.MS_NOEXEC, "mode=755"); err != nil {
+		return fmt.Errorf("failed to mount /dev: %v", err)
+	}
+	for _, name := range rootfsDevices {
+		target := filepath.Join(dev, name)
+		if err := os.WriteFile(target, nil, 0666); err != nil {
+			return fmt.Errorf("failed to create /dev/%s: %v", name, err)
+		}
+		if err := unix.Mount(filepath.Join("/dev", name), target, "", unix.MS_BIND, ""); err != nil {
+			return fmt.Errorf("failed to mount /dev/%s: %v", name, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(l.Merged, "proc"), 0555); err != nil {
+		return fmt.Errorf("failed to create /proc: %v", err)
+	}
+
+	oldRoot := filepath.Join(l.Merged, ".oldroot")
+	if err := os.MkdirAll(oldRoot, 0700); err != nil {
+		return fmt.Errorf("failed to create old root: %v", err)
+	}
+	if err := unix.PivotRoot(l.Merged, oldRoot); err != nil {
+		return fmt.Errorf("failed to pivot root: %v", err)
+	}
+	if err := unix.Chdir("/"); err != nil {
+		return fmt.Errorf("failed to pivot root: %v", err)
+	}
+	if err := unix.Unmount("/.oldroot", unix.MNT_DETACH); err != nil {
+		return fmt.Errorf("failed to detach old root: %v", err)
+	}
+	return os.Remove("/.oldroot")
+}
+
+// lookPath() helper func to find a command inside the new root, with the
+// job's PATH or a default one
+func lookPath(command string) (string, error) {
+	if strings.Contains(command, "/") {
+		return command, nil
+	}
+	if os.Getenv("PATH") == "" {
+		os.Setenv("PATH", defaultPath)
+		defer os.Unsetenv("PATH")
+	}
+	return exec.LookPath(command)
+}
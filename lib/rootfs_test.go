@@ -0,0 +1,104 @@
+package jobworker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testImage helper func to build a tiny image holding the host's sh and the libraries it needs
+func testImage(t *testing.T) string {
+	if os.Geteuid() != 0 {
+		t.Skip("mounting a root filesystem needs root")
+	}
+	shell, err := filepath.EvalSymlinks("/bin/sh")
+	assert.Nil(t, err)
+	files := []string{shell}
+	out, err := exec.Command("ldd", shell).Output()
+	if err != nil {
+		t.Skipf("cannot list the libraries of %s: %v", shell, err)
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		for _, field := range strings.Fields(line) {
+			if strings.HasPrefix(field, "/") {
+				files = append(files, field)
+			}
+		}
+	}
+
+	image := t.TempDir()
+	assert.Nil(t, os.Chmod(image, 0755))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		assert.Nil(t, err)
+		target := filepath.Join(image, file)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(target), 0755))
+		assert.Nil(t, os.WriteFile(target, data, 0755))
+	}
+	assert.Nil(t, os.MkdirAll(filepath.Join(image, "bin"), 0755))
+	if shell != "/bin/sh" {
+		assert.Nil(t, os.Symlink(shell, filepath.Join(image, "bin", "sh")))
+	}
+	return image
+}
+
+func TestRootFSValidate(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, RootFS{Image: dir, Dir: dir}.Validate())
+	assert.NotNil(t, RootFS{Image: "image", Dir: dir}.Validate())
+	assert.NotNil(t, RootFS{Image: filepath.Join(dir, "missing"), Dir: dir}.Validate())
+	assert.NotNil(t, ProcessOptions{RootFS: &RootFS{Image: dir, Dir: "layers"}}.Validate())
+}
+
+func TestImagesRootFS(t *testing.T) {
+	images := Images{Dir: t.TempDir(), LayerDir: filepath.Join(t.TempDir(), "layers")}
+	assert.Nil(t, os.Mkdir(filepath.Join(images.Dir, "alpine-3.18"), 0755))
+	assert.Nil(t, os.Mkdir(filepath.Join(images.LayerDir), 0755))
+	assert.Nil(t, os.Mkdir(filepath.Join(images.LayerDir, "stale"), 0755))
+	assert.Nil(t, images.Setup())
+	entries, err := os.ReadDir(images.LayerDir)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+
+	rootfs, err := images.RootFS("alpine-3.18")
+	assert.Nil(t, err)
+	assert.Equal(t, RootFS{Image: filepath.Join(images.Dir, "alpine-3.18"), Dir: images.LayerDir}, *rootfs)
+	for _, name := range []string{"", "debian", "..", ".hidden", "../alpine-3.18", "a/b"} {
+		_, err = images.RootFS(name)
+		assert.NotNil(t, err, "expected error for image %q", name)
+	}
+}
+
+func TestJobRootFS(t *testing.T) {
+	image := testImage(t)
+	layers := t.TempDir()
+	rootfs := &RootFS{Image: image, Dir: layers}
+
+	// the host's files are out of reach and the writes stay in the job's layer
+	script := "echo written > /written; read line < /written; echo $line; pwd; test -e /etc || echo hidden; test -c /dev/null && echo dev; test -e /proc/self && echo proc"
+	newJob := runJob(t, []string{"sh", "-c", script}, JobOptions{Process: ProcessOptions{RootFS: rootfs}})
+	assert.Equal(t, StateSucceeded, newJob.State())
+	assert.Equal(t, "written\n/\nhidden\ndev\nproc\n", string(readFrom(newJob.output, 0)))
+	_, err := os.Stat(filepath.Join(image, "written"))
+	assert.True(t, os.IsNotExist(err), "job wrote to the image")
+
+	// the layer is removed once the job is done
+	entries, err := os.ReadDir(layers)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+
+	// the working directory is inside the image, with the other options still applied
+	process := ProcessOptions{RootFS: rootfs, Dir: "/bin", Umask: umask(0077), Namespaces: AllNamespaces()}
+	newJob = runJob(t, []string{"sh", "-c", "pwd; umask"}, JobOptions{Process: process})
+	assert.Equal(t, StateSucceeded, newJob.State())
+	assert.Equal(t, "/bin\n0077\n", string(readFrom(newJob.output, 0)))
+
+	// commands that only exist on the host are not found
+	newJob = runJob(t, []string{"ls"}, JobOptions{Process: ProcessOptions{RootFS: rootfs}})
+	assert.Equal(t, StateFailed, newJob.State())
+	assert.Equal(t, childFailedCode, newJob.Result().ExitCode)
+}
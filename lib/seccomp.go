@@ -0,0 +1,224 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// seccomp constants missing from x/sys/unix, see linux/seccomp.h
+const (
+	seccompSetModeFilter   = 1
+	seccompFilterFlagTSYNC = 1
+	seccompRetKillProcess  = 0x80000000
+	seccompRetErrno        = 0x00050000
+	seccompRetAllow        = 0x7fff0000
+
+	// offsets in struct seccomp_data, only the low 32 bits of the first argument are checked
+	seccompDataNr   = 0
+	seccompDataArch = 4
+	seccompDataArg0 = 16
+)
+
+// namespaceCloneFlags are the clone flags creating new namespaces, which the
+// profiles reject like unshare and setns
+const namespaceCloneFlags = unix.CLONE_NEWNS | unix.CLONE_NEWCGROUP | unix.CLONE_NEWUTS | unix.CLONE_NEWIPC |
+	unix.CLONE_NEWUSER | unix.CLONE_NEWPID | unix.CLONE_NEWNET | unix.CLONE_NEWTIME
+
+// SeccompProfile names the system calls a job is not allowed to make, each
+// profile blocks everything the one before it does
+type SeccompProfile string
+
+const (
+	// SeccompNone leaves the job's system calls unfiltered
+	SeccompNone SeccompProfile = ""
+	// SeccompDefault blocks system calls that administer the host: mounts,
+	// namespaces, kernel modules, rebooting, the clock, keyrings, bpf and perf
+	SeccompDefault SeccompProfile = "default"
+	// SeccompNetworkOff also blocks sockets other than unix sockets, and
+	// io_uring since its requests can open and connect sockets too
+	SeccompNetworkOff SeccompProfile = "network-off"
+	// SeccompStrict also blocks tracing other processes, reading their memory,
+	// chroot and personality
+	SeccompStrict SeccompProfile = "strict"
+)
+
+// seccompProfiles lists the profiles from the weakest to the strictest
+var seccompProfiles = []SeccompProfile{SeccompNone, SeccompDefault, SeccompNetworkOff, SeccompStrict}
+
+// defaultDenied are the system calls blocked by every profile
+var defaultDenied = []uint32{
+	unix.SYS_MOUNT, unix.SYS_UMOUNT2, unix.SYS_PIVOT_ROOT, unix.SYS_FSOPEN, unix.SYS_FSMOUNT,
+	unix.SYS_FSCONFIG, unix.SYS_FSPICK, unix.SYS_MOVE_MOUNT, unix.SYS_OPEN_TREE, unix.SYS_MOUNT_SETATTR,
+	unix.SYS_UNSHARE, unix.SYS_SETNS,
+	unix.SYS_SWAPON, unix.SYS_SWAPOFF, unix.SYS_REBOOT, unix.SYS_KEXEC_LOAD, unix.SYS_KEXEC_FILE_LOAD,
+	unix.SYS_INIT_MODULE, unix.SYS_FINIT_MODULE, unix.SYS_DELETE_MODULE,
+	unix.SYS_SETTIMEOFDAY, unix.SYS_CLOCK_SETTIME, unix.SYS_ADJTIMEX, unix.SYS_CLOCK_ADJTIME,
+	unix.SYS_SETHOSTNAME, unix.SYS_SETDOMAINNAME,
+	unix.SYS_KEYCTL, unix.SYS_ADD_KEY, unix.SYS_REQUEST_KEY,
+	unix.SYS_BPF, unix.SYS_PERF_EVENT_OPEN, unix.SYS_USERFAULTFD, unix.SYS_FANOTIFY_INIT,
+	unix.SYS_ACCT, unix.SYS_QUOTACTL, unix.SYS_SYSLOG, unix.SYS_VHANGUP, unix.SYS_NFSSERVCTL,
+	unix.SYS_OPEN_BY_HANDLE_AT, unix.SYS_NAME_TO_HANDLE_AT, unix.SYS_LOOKUP_DCOOKIE,
+}
+
+// networkDenied are the system calls the network-off and strict profiles
+// block, besides sockets other than unix sockets
+var networkDenied = []uint32{
+	unix.SYS_IO_URING_SETUP, unix.SYS_IO_URING_ENTER, unix.SYS_IO_URING_REGISTER,
+}
+
+// strictDenied are the system calls only the strict profile blocks
+var strictDenied = []uint32{
+	unix.SYS_PTRACE, unix.SYS_PROCESS_VM_READV, unix.SYS_PROCESS_VM_WRITEV, unix.SYS_KCMP,
+	unix.SYS_CHROOT, unix.SYS_PERSONALITY,
+}
+
+// Validate checks the profile exists
+func (p SeccompProfile) Validate() error {
+	if p.level() < 0 {
+		return fmt.Errorf("unknown seccomp profile %q, expected default, network-off or strict", string(p))
+	}
+	if p != SeccompNone && auditArch == 0 {
+		return fmt.Errorf("seccomp profiles are not supported on %s", runtime.GOARCH)
+	}
+	return nil
+}
+
+// level() helper func to get the position of the profile in seccompProfiles, -1 when unknown
+func (p SeccompProfile) level() int {
+	for i, profile := range seccompProfiles {
+		if p == profile {
+			return i
+		}
+	}
+	return -1
+}
+
+// Max returns the stricter of p and other
+func (p SeccompProfile) Max(other SeccompProfile) SeccompProfile {
+	if other.level() > p.level() {
+		return other
+	}
+	return p
+}
+
+// filter() helper func to build the BPF program of the profile. Blocked system
+// calls fail with EPERM, other architectures than the server's are killed
+func (p SeccompProfile) filter() []unix.SockFilter {
+	ret := func(value uint32) unix.SockFilter {
+		return unix.SockFilter{Code: unix.BPF_RET | unix.BPF_K, K: value}
+	}
+	load := func(offset uint32) unix.SockFilter {
+		return unix.SockFilter{Code: unix.BPF_LD | unix.BPF_W | unix.BPF_ABS, K: offset}
+	}
+	// jump over the next instruction unless the accumulator equals value
+	skipUnless := func(value uint32) unix.SockFilter {
+		return unix.SockFilter{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, K: value, Jf: 1}
+	}
+	// deny() returns the instructions failing system call nr with errno
+	deny := func(nr uint32, errno unix.Errno) []unix.SockFilter {
+		return []unix.SockFilter{skipUnless(nr), ret(seccompRetErrno | uint32(errno))}
+	}
+
+	program := []unix.SockFilter{
+		load(seccompDataArch),
+		{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, K: auditArch, Jt: 1},
+		ret(seccompRetKillProcess),
+		load(seccompDataNr),
+	}
+	if syscallArchBit != 0 {
+		// x32 system calls are other numbers for the same calls
+		program = append(program,
+			unix.SockFilter{Code: unix.BPF_JMP | unix.BPF_JSET | unix.BPF_K, K: syscallArchBit, Jf: 1},
+			ret(seccompRetErrno|uint32(unix.EPERM)))
+	}
+
+	denied := append(append([]uint32{}, defaultDenied...), archDenied...)
+	if p == SeccompNetworkOff || p == SeccompStrict {
+		denied = append(denied, networkDenied...)
+	}
+	if p == SeccompStrict {
+		denied = append(denied, strictDenied...)
+	}
+	for _, nr := range denied {
+		program = append(program, deny(nr, unix.EPERM)...)
+	}
+	// the flags of clone3 are behind a pointer seccomp cannot follow, ENOSYS
+	// makes the C library fall back to clone, whose flags can be checked
+	program = append(program, deny(unix.SYS_CLONE3, unix.ENOSYS)...)
+	program = append(program,
+		unix.SockFilter{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, K: unix.SYS_CLONE, Jf: 4},
+		load(seccompDataArg0),
+		unix.SockFilter{Code: unix.BPF_JMP | unix.BPF_JSET | unix.BPF_K, K: namespaceCloneFlags, Jf: 1},
+		ret(seccompRetErrno|uint32(unix.EPERM)),
+		ret(seccompRetAllow))
+	if p == SeccompNetworkOff || p == SeccompStrict {
+		program = append(program,
+			unix.SockFilter{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, K: unix.SYS_SOCKET, Jf: 4},
+			load(seccompDataArg0),
+			unix.SockFilter{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, K: unix.AF_UNIX, Jt: 1},
+			ret(seccompRetErrno|uint32(unix.EPERM)),
+			ret(seccompRetAllow))
+	}
+	return append(program, ret(seccompRetAllow))
+}
+
+// install() helper func run by the child process to filter its system calls and
+// those of everything it starts, the last step before the command runs.
+// no_new_privs is required to install a filter without CAP_SYS_ADMIN and keeps
+// setuid programs from escaping it
+func (p SeccompProfile) install() error {
+	if p == SeccompNone {
+		return nil
+	}
+	// no_new_privs is set per thread, the filter has to be installed from the same one
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs: %v", err)
+	}
+	program := p.filter()
+	prog := unix.SockFprog{Len: uint16(len(program)), Filter: &program[0]}
+	// TSYNC applies the filter to every thread of the Go runtime, not just this one
+	_, _, errno := unix.Syscall(unix.SYS_SECCOMP, seccompSetModeFilter, seccompFilterFlagTSYNC, uintptr(unsafe.Pointer(&prog)))
+	if errno != 0 {
+		return fmt.Errorf("failed to install seccomp profile %s: %v", p, errno)
+	}
+	return nil
+}
+
+// SeccompPolicy is the administrator defined minimum seccomp profile of jobs,
+// jobs can always ask for a stricter one
+// Default: profile every job runs with at least
+// Users: per user profiles replacing Default for that user
+type SeccompPolicy struct {
+	Default SeccompProfile            `json:"default"`
+	Users   map[string]SeccompProfile `json:"users,omitempty"`
+}
+
+// Validate checks every profile of the policy exists
+func (p SeccompPolicy) Validate() error {
+	if err := p.Default.Validate(); err != nil {
+		return err
+	}
+	for username, profile := range p.Users {
+		if err := profile.Validate(); err != nil {
+			return fmt.Errorf("user %s: %v", username, err)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the profile a user's job runs with, the requested one or
+// the policy's when that is stricter
+func (p SeccompPolicy) Resolve(username string, requested SeccompProfile) SeccompProfile {
+	required, ok := p.Users[username]
+	if !ok {
+		required = p.Default
+	}
+	return requested.Max(required)
+}
@@ -0,0 +1,17 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import "golang.org/x/sys/unix"
+
+// auditArch is the architecture seccomp filters accept system calls from
+const auditArch = unix.AUDIT_ARCH_X86_64
+
+// syscallArchBit marks x32 system calls, which share the architecture of x86_64
+const syscallArchBit = 0x40000000
+
+// archDenied are the system calls blocked by every profile that only exist on x86_64
+var archDenied = []uint32{
+	unix.SYS_IOPL, unix.SYS_IOPERM, unix.SYS_USELIB, unix.SYS_CREATE_MODULE,
+	unix.SYS_GET_KERNEL_SYMS, unix.SYS_QUERY_MODULE, unix.SYS__SYSCTL,
+}
@@ -0,0 +1,14 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import "golang.org/x/sys/unix"
+
+// auditArch is the architecture seccomp filters accept system calls from
+const auditArch = unix.AUDIT_ARCH_AARCH64
+
+// syscallArchBit marks system calls of another ABI on the same architecture, arm64 has none
+const syscallArchBit = 0
+
+// archDenied are the system calls blocked by every profile that only exist on arm64
+var archDenied []uint32
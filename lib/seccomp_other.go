@@ -0,0 +1,15 @@
+// Copyright 2023 Steven Bui
+
+//go:build !amd64 && !arm64
+// +build !amd64,!arm64
+
+package jobworker
+
+// auditArch is zero where seccomp profiles are not supported, they fail validation
+const auditArch = 0
+
+// syscallArchBit is unused where seccomp profiles are not supported
+const syscallArchBit = 0
+
+// archDenied is unused where seccomp profiles are not supported
+var archDenied []uint32
@@ -0,0 +1,63 @@
+package jobworker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+)
+
+func TestSeccompPolicyResolve(t *testing.T) {
+	policy := SeccompPolicy{
+		Default: SeccompDefault,
+		Users:   map[string]SeccompProfile{"carl": SeccompStrict, "root": SeccompNone},
+	}
+	assert.Nil(t, policy.Validate())
+	assert.NotNil(t, SeccompPolicy{Users: map[string]SeccompProfile{"bob": "paranoid"}}.Validate())
+
+	assert.Equal(t, SeccompDefault, policy.Resolve("alice", SeccompNone))
+	assert.Equal(t, SeccompNetworkOff, policy.Resolve("alice", SeccompNetworkOff))
+	// carl's policy replaces the default and a weaker request does not lower it
+	assert.Equal(t, SeccompStrict, policy.Resolve("carl", SeccompNetworkOff))
+	assert.Equal(t, SeccompNone, policy.Resolve("root", SeccompNone))
+}
+
+func TestJobSeccomp(t *testing.T) {
+	if _, err := exec.LookPath("perl"); err != nil {
+		t.Skip("perl is needed to open sockets")
+	}
+	// io_uring_setup is called directly, perl has no binding for it
+	script := fmt.Sprintf(`unshare -U true 2>/dev/null || echo unshare blocked
+chroot / true 2>/dev/null || echo chroot blocked
+perl -MSocket -e 'socket(S, PF_INET, SOCK_STREAM, 0) or print "inet blocked\n"'
+perl -MSocket -e 'socket(S, PF_UNIX, SOCK_STREAM, 0) or print "unix blocked\n"'
+perl -e '$p = "\0" x 120; syscall(%d, 1, $p) >= 0 or print "io_uring blocked\n"'`, unix.SYS_IO_URING_SETUP)
+	run := func(profile SeccompProfile) string {
+		newJob := runJob(t, []string{"sh", "-c", script}, JobOptions{Process: ProcessOptions{Seccomp: profile}})
+		assert.Equal(t, StateSucceeded, newJob.State())
+		return string(readFrom(newJob.output, 0))
+	}
+
+	// chroot as an unprivileged user fails anyway, only the strict profile is checked for it
+	output := run(SeccompDefault)
+	assert.Contains(t, output, "unshare blocked\n")
+	assert.NotContains(t, output, "inet blocked")
+	assert.NotContains(t, output, "io_uring blocked")
+	assert.Equal(t, "unshare blocked\nchroot blocked\ninet blocked\nio_uring blocked\n", run(SeccompStrict))
+	// io_uring requests can open and connect inet sockets without calling socket
+	output = run(SeccompNetworkOff)
+	assert.Contains(t, output, "unshare blocked\n")
+	assert.Contains(t, output, "inet blocked\n")
+	assert.NotContains(t, output, "unix blocked")
+	assert.Contains(t, output, "io_uring blocked\n")
+
+	// the profile also applies to the command started by pid 1 of a pid namespace
+	if os.Geteuid() == 0 {
+		process := ProcessOptions{Seccomp: SeccompNetworkOff, Namespaces: Namespaces{PID: true}}
+		newJob := runJob(t, []string{"sh", "-c", script}, JobOptions{Process: process})
+		assert.Contains(t, string(readFrom(newJob.output, 0)), "inet blocked\n")
+	}
+}
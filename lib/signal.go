@@ -0,0 +1,33 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// ParseSignal returns the signal with the given name, i.e. SIGTERM or TERM
+func ParseSignal(name string) (syscall.Signal, error) {
+	name = strings.ToUpper(name)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	sig := unix.SignalNum(name)
+	if sig == 0 {
+		return 0, fmt.Errorf("unknown signal %q", name)
+	}
+	return sig, nil
+}
+
+// signalGroup() helper func to send a signal to every process in the job's process group
+func signalGroup(pgid int, sig syscall.Signal) error {
+	err := unix.Kill(-pgid, sig)
+	if err != nil && err != unix.ESRCH {
+		return fmt.Errorf("failed to send %s to process group %d: %v", unix.SignalName(sig), pgid, err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package jobworker
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSignal(t *testing.T) {
+	for _, name := range []string{"SIGTERM", "TERM", "term"} {
+		sig, err := ParseSignal(name)
+		assert.Nil(t, err)
+		assert.Equal(t, syscall.SIGTERM, sig)
+	}
+
+	sig, err := ParseSignal("SIGUSR1")
+	assert.Nil(t, err)
+	assert.Equal(t, syscall.SIGUSR1, sig)
+
+	_, err = ParseSignal("SIGFOO")
+	assert.NotNil(t, err)
+	_, err = ParseSignal("")
+	assert.NotNil(t, err)
+}
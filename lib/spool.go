@@ -0,0 +1,434 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// spoolHeaderSize is the size of a record header: offset, time, length and stream
+	spoolHeaderSize = 8 + 8 + 4 + 1
+	// spoolIndexInterval is how much output there is between two index entries
+	spoolIndexInterval = 64 * 1024
+	// readSize is roughly the most output read from a log at once
+	readSize = 4 * ChunkSize
+)
+
+// OutputPolicy decides what happens to output written past a job's maximum size
+type OutputPolicy string
+
+const (
+	// PolicyRotate drops the oldest output to keep the latest
+	PolicyRotate OutputPolicy = "rotate"
+	// PolicyTruncate keeps the first output and drops the rest
+	PolicyTruncate OutputPolicy = "truncate"
+)
+
+// OutputOptions sets where a job's output is kept
+// Dir: folder the output is spooled to, kept in memory when empty
+// MaxSize: most bytes of output kept, zero for no limit
+// Policy: what to do with output past MaxSize, rotate when empty
+type OutputOptions struct {
+	Dir     string       `json:"dir,omitempty"`
+	MaxSize int64        `json:"maxSize,omitempty"`
+	Policy  OutputPolicy `json:"policy,omitempty"`
+}
+
+// Validate checks the options are well formed
+func (o OutputOptions) Validate() error {
+	if o.MaxSize < 0 {
+		return fmt.Errorf("output max size cannot be negative")
+	}
+	if o.MaxSize > 0 && o.MaxSize < 2*ChunkSize {
+		return fmt.Errorf("output max size must be at least %d", 2*ChunkSize)
+	}
+	switch o.Policy {
+	case "", PolicyRotate, PolicyTruncate:
+		return nil
+	}
+	return fmt.Errorf("unknown output policy %q", o.Policy)
+}
+
+// outputLog is where a Broadcaster keeps the output, the Broadcaster serializes the calls
+type outputLog interface {
+	// append stores the chunk, its Offset is the end of the log
+	append(chunk OutputChunk) error
+	// read returns about max bytes of output starting at offset,
+	// the first chunk is trimmed to start at offset
+	read(offset int64, max int) ([]OutputChunk, error)
+	// start is the offset of the oldest output still kept
+	start() int64
+	// end is the offset right after the last output
+	end() int64
+	close() error
+}
+
+// truncateChunk() helper func to fit a chunk under the maximum size, false when
+// nothing of it can be kept
+func truncateChunk(chunk *OutputChunk, size int64, opts OutputOptions) bool {
+	if opts.Policy != PolicyTruncate || opts.MaxSize == 0 {
+		return true
+	}
+	if size >= opts.MaxSize {
+		return false
+	}
+	if room := opts.MaxSize - size; int64(len(chunk.Data)) > room {
+		chunk.Data = chunk.Data[:room]
+	}
+	return true
+}
+
+// trimChunks() helper func to cut the chunks before offset and after max bytes
+func trimChunks(chunks []OutputChunk, offset int64, max int) []OutputChunk {
+	var out []OutputChunk
+	total := 0
+	for _, chunk := range chunks {
+		if skip := offset - chunk.Offset; skip > 0 {
+			if skip >= int64(len(chunk.Data)) {
+				continue
+			}
+			chunk.Data = chunk.Data[skip:]
+			chunk.Offset = offset
+		}
+		out = append(out, chunk)
+		total += len(chunk.Data)
+		if total >= max {
+			break
+		}
+	}
+	return out
+}
+
+// memoryLog keeps the output in memory
+type memoryLog struct {
+	chunks []OutputChunk
+	size   int64
+	opts   OutputOptions
+}
+
+func newMemoryLog(opts OutputOptions) *memoryLog {
+	return &memoryLog{opts: opts}
+}
+
+func (m *memoryLog) append(chunk OutputChunk) error {
+	if !truncateChunk(&chunk, m.size, m.opts) {
+		return nil
+	}
+	m.chunks = append(m.chunks, chunk)
+	m.size += int64(len(chunk.Data))
+
+	if m.opts.Policy != PolicyTruncate && m.opts.MaxSize > 0 {
+		for len(m.chunks) > 1 && m.size-m.chunks[0].Offset > m.opts.MaxSize {
+			m.chunks = m.chunks[1:]
+		}
+	}
+	return nil
+}
+
+func (m *memoryLog) read(offset int64, max int) ([]OutputChunk, error) {
+	i := sort.Search(len(m.chunks), func(i int) bool {
+		chunk := m.chunks[i]
+		return chunk.Offset+int64(len(chunk.Data)) > offset
+	})
+	return trimChunks(m.chunks[i:], offset, max), nil
+}
+
+func (m *memoryLog) start() int64 {
+	if len(m.chunks) == 0 {
+		return m.size
+	}
+	return m.chunks[0].Offset
+}
+
+func (m *memoryLog) end() int64 {
+	return m.size
+}
+
+func (m *memoryLog) close() error {
+	return nil
+}
+
+// spoolSegment is one file of a spool
+// base: offset of the segment's first byte of output
+// size: bytes of output in the segment
+// pos: size of the file, headers included
+// index: position of a record about every spoolIndexInterval bytes of output
+type spoolSegment struct {
+	path  string
+	base  int64
+	size  int64
+	pos   int64
+	index []spoolIndex
+}
+
+type spoolIndex struct {
+	offset int64
+	pos    int64
+}
+
+// fileLog spools the output to <dir>/<jobID>.out. With the rotate policy the
+// output is split in two segments of half the maximum size, the older one is
+// renamed to <jobID>.out.1 and replaced when the current one is full
+type fileLog struct {
+	path     string
+	file     *os.File
+	segments []*spoolSegment
+	opts     OutputOptions
+}
+
+// newFileLog creates an empty spool for a job
+func newFileLog(jobID string, opts OutputOptions) (*fileLog, error) {
+	if err := os.MkdirAll(opts.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create output folder: %v", err)
+	}
+	path := filepath.Join(opts.Dir, jobID+".out")
+	os.Remove(path + ".1")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %v", err)
+	}
+	return &fileLog{
+		path:     path,
+		file:     file,
+		segments: []*spoolSegment{{path: path}},
+		opts:     opts,
+	}, nil
+}
+
+// openFileLog opens the spool of a job written before, read only
+func openFileLog(jobID string, opts OutputOptions) (*fileLog, error) {
+	path := filepath.Join(opts.Dir, jobID+".out")
+	f := &fileLog{path: path, opts: opts}
+	for _, segmentPath := range []string{path + ".1", path} {
+		segment, err := scanSegment(segmentPath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// a segment without output starts where the one before it ends
+		if segment.size == 0 && len(f.segments) > 0 {
+			previous := f.segments[len(f.segments)-1]
+			segment.base = previous.base + previous.size
+		}
+		f.segments = append(f.segments, segment)
+	}
+	if len(f.segments) == 0 {
+		f.segments = []*spoolSegment{{path: path}}
+	}
+	return f, nil
+}
+
+// removeOutput() helper func to delete the spool of a job, nothing when its output was kept in memory
+func removeOutput(jobID string, opts OutputOptions) error {
+	if opts.Dir == "" {
+		return nil
+	}
+	path := filepath.Join(opts.Dir, jobID+".out")
+	for _, segmentPath := range []string{path, path + ".1"} {
+		if err := os.Remove(segmentPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove output: %v", err)
+		}
+	}
+	return nil
+}
+
+// scanSegment() helper func to rebuild the index of a segment file.
+// A record torn by a crash ends the segment
+func scanSegment(path string) (*spoolSegment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	segment := &spoolSegment{path: path}
+	reader := bufio.NewReader(file)
+	for {
+		chunk, n, err := readRecord(reader)
+		if err != nil {
+			break
+		}
+		if segment.pos == 0 {
+			segment.base = chunk.Offset
+		}
+		segment.addIndex(chunk.Offset)
+		segment.size += int64(len(chunk.Data))
+		segment.pos += n
+	}
+	return segment, nil
+}
+
+// addIndex() helper func to index the record about to be added at the segment's end
+func (s *spoolSegment) addIndex(offset int64) {
+	if len(s.index) == 0 || offset-s.index[len(s.index)-1].offset >= spoolIndexInterval {
+		s.index = append(s.index, spoolIndex{offset: offset, pos: s.pos})
+	}
+}
+
+func (f *fileLog) current() *spoolSegment {
+	return f.segments[len(f.segments)-1]
+}
+
+func (f *fileLog) append(chunk OutputChunk) error {
+	if f.file == nil {
+		return fmt.Errorf("output file is closed")
+	}
+	if !truncateChunk(&chunk, f.end(), f.opts) {
+		return nil
+	}
+	if f.opts.Policy != PolicyTruncate && f.opts.MaxSize > 0 {
+		if segment := f.current(); segment.size > 0 && segment.size+int64(len(chunk.Data)) > f.opts.MaxSize/2 {
+			if err := f.rotate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	header := make([]byte, spoolHeaderSize)
+	binary.BigEndian.PutUint64(header[0:], uint64(chunk.Offset))
+	binary.BigEndian.PutUint64(header[8:], uint64(chunk.Time.UnixNano()))
+	binary.BigEndian.PutUint32(header[16:], uint32(len(chunk.Data)))
+	header[20] = byte(chunk.Stream)
+	if _, err := f.file.Write(append(header, chunk.Data...)); err != nil {
+		return fmt.Errorf("failed to write output file: %v", err)
+	}
+
+	segment := f.current()
+	if segment.size == 0 {
+		segment.base = chunk.Offset
+	}
+	segment.addIndex(chunk.Offset)
+	segment.size += int64(len(chunk.Data))
+	segment.pos += int64(spoolHeaderSize + len(chunk.Data))
+	return nil
+}
+
+// rotate() helper func to replace the older segment with the current one
+func (f *fileLog) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %v", err)
+	}
+	if err := os.Rename(f.path, f.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate output file: %v", err)
+	}
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0600)
+	if err != nil {
+		f.file = nil
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	f.file = file
+
+	previous := f.current()
+	previous.path = f.path + ".1"
+	f.segments = []*spoolSegment{previous, {path: f.path, base: previous.base + previous.size}}
+	return nil
+}
+
+func (f *fileLog) read(offset int64, max int) ([]OutputChunk, error) {
+	var out []OutputChunk
+	total := 0
+	for _, segment := range f.segments {
+		if segment.base+segment.size <= offset || segment.size == 0 {
+			continue
+		}
+		chunks, err := segment.read(offset, max-total)
+		if err != nil {
+			return nil, err
+		}
+		for _, chunk := range chunks {
+			total += len(chunk.Data)
+		}
+		out = append(out, chunks...)
+		if total >= max {
+			break
+		}
+	}
+	return out, nil
+}
+
+// read() helper func to read the records of a segment from the index entry before offset
+func (s *spoolSegment) read(offset int64, max int) ([]OutputChunk, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open output file: %v", err)
+	}
+	defer file.Close()
+
+	i := sort.Search(len(s.index), func(i int) bool { return s.index[i].offset > offset }) - 1
+	pos := int64(0)
+	if i >= 0 {
+		pos = s.index[i].pos
+	}
+	reader := bufio.NewReader(io.NewSectionReader(file, pos, s.pos-pos))
+
+	var chunks []OutputChunk
+	total := 0
+	for total < max {
+		chunk, _, err := readRecord(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read output file: %v", err)
+		}
+		if chunk.Offset+int64(len(chunk.Data)) <= offset {
+			continue
+		}
+		chunks = append(chunks, chunk)
+		total += len(chunk.Data)
+	}
+	return trimChunks(chunks, offset, max), nil
+}
+
+// readRecord() helper func to read a chunk and its header, returning the bytes read
+func readRecord(r io.Reader) (OutputChunk, int64, error) {
+	header := make([]byte, spoolHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return OutputChunk{}, 0, err
+	}
+	length := binary.BigEndian.Uint32(header[16:])
+	if length > ChunkSize {
+		return OutputChunk{}, 0, fmt.Errorf("corrupt output record of %d bytes", length)
+	}
+	chunk := OutputChunk{
+		Offset: int64(binary.BigEndian.Uint64(header[0:])),
+		Time:   time.Unix(0, int64(binary.BigEndian.Uint64(header[8:]))),
+		Data:   make([]byte, length),
+		Stream: Stream(header[20]),
+	}
+	if _, err := io.ReadFull(r, chunk.Data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return OutputChunk{}, 0, err
+	}
+	return chunk, int64(spoolHeaderSize + len(chunk.Data)), nil
+}
+
+func (f *fileLog) start() int64 {
+	return f.segments[0].base
+}
+
+func (f *fileLog) end() int64 {
+	segment := f.current()
+	return segment.base + segment.size
+}
+
+func (f *fileLog) close() error {
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
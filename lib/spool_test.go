@@ -0,0 +1,130 @@
+package jobworker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeLines helper func to write numbered lines of 1KB each, one chunk per line
+func writeLines(b *Broadcaster, n int) []byte {
+	var all []byte
+	for i := 0; i < n; i++ {
+		line := []byte(fmt.Sprintf("%04d%s\n", i, bytes.Repeat([]byte("x"), 1019)))
+		b.Write(OutputChunk{Stream: Stream(i % 2), Time: time.Now(), Data: line})
+		all = append(all, line...)
+	}
+	return all
+}
+
+func readFrom(b *Broadcaster, offset int64) []byte {
+	var data []byte
+	for chunk := range b.SubscribeFrom(context.Background(), offset, false) {
+		data = append(data, chunk.Data...)
+	}
+	return data
+}
+
+func TestOutputOptionsValidate(t *testing.T) {
+	assert.Nil(t, OutputOptions{}.Validate())
+	assert.Nil(t, OutputOptions{MaxSize: 1 << 20, Policy: PolicyTruncate}.Validate())
+	assert.NotNil(t, OutputOptions{MaxSize: -1}.Validate())
+	assert.NotNil(t, OutputOptions{MaxSize: 1024}.Validate())
+	assert.NotNil(t, OutputOptions{Policy: "drop"}.Validate())
+}
+
+func TestSpoolRead(t *testing.T) {
+	dir := t.TempDir()
+	b, err := NewOutput("job1", OutputOptions{Dir: dir})
+	assert.Nil(t, err)
+	// enough output for the index to have several entries
+	all := writeLines(b, 300)
+
+	assert.Equal(t, all, readFrom(b, 0))
+	assert.Equal(t, all[100000:], readFrom(b, 100000))
+	assert.Empty(t, readFrom(b, int64(len(all))))
+
+	chunks := b.Chunks()
+	assert.Len(t, chunks, 300)
+	assert.Equal(t, Stderr, chunks[1].Stream)
+	assert.Equal(t, int64(1024), chunks[1].Offset)
+	assert.Equal(t, int64(len(all)-2*1024), b.TailOffset(2))
+
+	// the output can be read back after the job is gone
+	b.Close()
+	reopened, err := OpenOutput("job1", OutputOptions{Dir: dir})
+	assert.Nil(t, err)
+	assert.Equal(t, all, readFrom(reopened, 0))
+	assert.Equal(t, all[5000:], readFrom(reopened, 5000))
+	assert.Equal(t, chunks[1].Time.UnixNano(), reopened.Chunks()[1].Time.UnixNano())
+}
+
+func TestSpoolRotate(t *testing.T) {
+	dir := t.TempDir()
+	opts := OutputOptions{Dir: dir, MaxSize: 2 * ChunkSize, Policy: PolicyRotate}
+	b, err := NewOutput("job1", opts)
+	assert.Nil(t, err)
+	all := writeLines(b, 200)
+
+	kept := readFrom(b, 0)
+	assert.True(t, len(kept) <= int(opts.MaxSize))
+	assert.True(t, len(kept) >= int(opts.MaxSize)/2)
+	assert.Equal(t, all[len(all)-len(kept):], kept)
+
+	// reading from a rotated offset starts at the oldest output kept
+	chunk := <-b.SubscribeFrom(context.Background(), 10, false)
+	assert.Equal(t, int64(len(all)-len(kept)), chunk.Offset)
+
+	var onDisk int64
+	for _, name := range []string{"job1.out", "job1.out.1"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		assert.Nil(t, err)
+		onDisk += info.Size()
+	}
+	assert.True(t, onDisk <= opts.MaxSize+200*spoolHeaderSize)
+
+	b.Close()
+	reopened, err := OpenOutput("job1", opts)
+	assert.Nil(t, err)
+	assert.Equal(t, kept, readFrom(reopened, 0))
+}
+
+func TestSpoolTruncate(t *testing.T) {
+	opts := OutputOptions{Dir: t.TempDir(), MaxSize: 2 * ChunkSize, Policy: PolicyTruncate}
+	b, err := NewOutput("job1", opts)
+	assert.Nil(t, err)
+	all := writeLines(b, 200)
+
+	assert.Equal(t, all[:opts.MaxSize], readFrom(b, 0))
+}
+
+func TestMemoryOutputLimits(t *testing.T) {
+	b, err := NewOutput("job1", OutputOptions{MaxSize: 2 * ChunkSize})
+	assert.Nil(t, err)
+	all := writeLines(b, 200)
+	kept := readFrom(b, 0)
+	assert.True(t, len(kept) <= 2*ChunkSize)
+	assert.Equal(t, all[len(all)-len(kept):], kept)
+
+	b, err = NewOutput("job2", OutputOptions{MaxSize: 2 * ChunkSize, Policy: PolicyTruncate})
+	assert.Nil(t, err)
+	all = writeLines(b, 200)
+	assert.Equal(t, all[:2*ChunkSize], readFrom(b, 0))
+}
+
+func TestJobSpoolOutput(t *testing.T) {
+	dir := t.TempDir()
+	newJob := runJob(t, []string{"sh", "-c", "yes | head -c 1000000"}, JobOptions{Output: OutputOptions{Dir: dir, MaxSize: 256 * 1024}})
+
+	assert.Equal(t, StateSucceeded, newJob.State())
+	output := readFrom(newJob.output, 0)
+	assert.True(t, len(output) <= 256*1024)
+	assert.Equal(t, "y\ny\n", string(output[len(output)-4:]))
+	assert.FileExists(t, filepath.Join(dir, newJob.JobID+".out"))
+}
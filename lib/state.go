@@ -0,0 +1,84 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"fmt"
+	"time"
+)
+
+// JobState represents where a job is in its life cycle
+type JobState int
+
+const (
+	StatePending JobState = iota
+	StateRunning
+	StateSucceeded
+	StateFailed
+	StateStopping
+	StateStopped
+	StateKilled
+	StateLost
+	StatePaused
+)
+
+var stateNames = map[JobState]string{
+	StatePending:   "pending",
+	StateRunning:   "running",
+	StateSucceeded: "succeeded",
+	StateFailed:    "failed",
+	StateStopping:  "stopping",
+	StateStopped:   "stopped",
+	StateKilled:    "killed",
+	StateLost:      "lost",
+	StatePaused:    "paused",
+}
+
+// stateTransitions lists the states each state is allowed to move to.
+// States without an entry are final. A paused job can still end when it
+// exits right before being frozen or is killed
+var stateTransitions = map[JobState][]JobState{
+	StatePending:  {StateRunning, StateFailed, StateStopped, StateLost},
+	StateRunning:  {StateSucceeded, StateFailed, StateStopping, StateKilled, StateLost, StatePaused},
+	StatePaused:   {StateRunning, StateSucceeded, StateFailed, StateStopping, StateKilled, StateLost},
+	StateStopping: {StateStopped, StateLost},
+}
+
+func (s JobState) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", int(s))
+}
+
+// ParseJobState returns the state with the given name
+func ParseJobState(name string) (JobState, error) {
+	for state, stateName := range stateNames {
+		if stateName == name {
+			return state, nil
+		}
+	}
+	return StatePending, fmt.Errorf("unknown job state %q", name)
+}
+
+// IsFinal returns true when the job can no longer change state
+func (s JobState) IsFinal() bool {
+	_, ok := stateTransitions[s]
+	return !ok
+}
+
+// CanTransition returns true when a job is allowed to move from s to next
+func (s JobState) CanTransition(next JobState) bool {
+	for _, allowed := range stateTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// StateChange is an entry of a job's state history
+type StateChange struct {
+	State JobState
+	Time  time.Time
+}
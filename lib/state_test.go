@@ -0,0 +1,41 @@
+package jobworker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateTransitions(t *testing.T) {
+	assert.True(t, StatePending.CanTransition(StateRunning))
+	assert.True(t, StateRunning.CanTransition(StateStopping))
+	assert.True(t, StateStopping.CanTransition(StateStopped))
+	assert.True(t, StateRunning.CanTransition(StatePaused))
+	assert.True(t, StatePaused.CanTransition(StateRunning))
+	assert.True(t, StatePaused.CanTransition(StateStopping))
+
+	assert.False(t, StateRunning.CanTransition(StatePending))
+	assert.False(t, StateStopping.CanTransition(StateSucceeded))
+	assert.False(t, StateSucceeded.CanTransition(StateRunning))
+	assert.False(t, StatePending.CanTransition(StatePaused))
+	assert.False(t, StateStopping.CanTransition(StatePaused))
+
+	for _, state := range []JobState{StateSucceeded, StateFailed, StateStopped, StateKilled, StateLost} {
+		assert.True(t, state.IsFinal(), "%s should be final", state)
+	}
+	for _, state := range []JobState{StatePending, StateRunning, StatePaused, StateStopping} {
+		assert.False(t, state.IsFinal(), "%s should not be final", state)
+	}
+}
+
+func TestParseJobState(t *testing.T) {
+	for state, name := range stateNames {
+		assert.Equal(t, name, state.String())
+		parsed, err := ParseJobState(name)
+		assert.Nil(t, err)
+		assert.Equal(t, state, parsed)
+	}
+
+	_, err := ParseJobState("sleeping")
+	assert.NotNil(t, err)
+}
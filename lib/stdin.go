@@ -0,0 +1,82 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"fmt"
+	"os"
+)
+
+// openStdin() helper func to create the pipe a job reads its stdin from. It is
+// created with the job so input can be written before the process starts
+func (j *JobInfo) openStdin() error {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdin pipe: %v", err)
+	}
+	j.stdinReader = reader
+	j.stdinWriter = writer
+	return nil
+}
+
+// closeStdinReader() helper func to drop the server's copy of the read end once
+// the process has its own, or when the process will never run
+func (j *JobInfo) closeStdinReader() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.stdinReader != nil {
+		j.stdinReader.Close()
+		j.stdinReader = nil
+	}
+}
+
+// closeStdin() helper func to close both ends of the stdin pipe once the job has ended
+func (j *JobInfo) closeStdin() {
+	j.closeStdinReader()
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.stdinWriter != nil {
+		j.stdinWriter.Close()
+		j.stdinWriter = nil
+	}
+}
+
+// WriteStdin writes data to the job's stdin, blocking until the job has read
+// enough of it to fit in the pipe
+func (jw *JobInfo) WriteStdin(data []byte) error {
+	jw.mutex.Lock()
+	writer := jw.stdinWriter
+	hasStdin := jw.stdin
+	jw.mutex.Unlock()
+
+	if !hasStdin {
+		return fmt.Errorf("job %s was not started with stdin", jw.JobID)
+	}
+	if writer == nil {
+		return fmt.Errorf("stdin of job %s is closed", jw.JobID)
+	}
+	// closing the writer while a write blocks makes the write fail instead of hanging
+	if _, err := writer.Write(data); err != nil {
+		return fmt.Errorf("failed to write stdin of job %s: %v", jw.JobID, err)
+	}
+	return nil
+}
+
+// CloseStdin closes the job's stdin, the job reads EOF once it has read everything written.
+// A terminal stays open, the job reads EOF from a ctrl-D typed at the start of a line instead
+func (jw *JobInfo) CloseStdin() error {
+	if jw.tty {
+		return jw.WriteStdin([]byte{eotChar})
+	}
+	jw.mutex.Lock()
+	defer jw.mutex.Unlock()
+	if !jw.stdin {
+		return fmt.Errorf("job %s was not started with stdin", jw.JobID)
+	}
+	if jw.stdinWriter == nil {
+		return nil
+	}
+	err := jw.stdinWriter.Close()
+	jw.stdinWriter = nil
+	return err
+}
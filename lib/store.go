@@ -0,0 +1,302 @@
+// Copyright 2023 Steven Bui
+
+package jobworker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// StoreFile is the name of the log a LogStore appends to in its folder
+const StoreFile = "jobs.log"
+
+// JobStore persists jobs so they survive a server restart
+type JobStore interface {
+	// SaveJob records a new job or a change of its state or result
+	SaveJob(record JobRecord) error
+	// Load returns every job saved, oldest first
+	Load() ([]JobRecord, error)
+	// Compact replaces everything saved with records, the latest one of every job
+	Compact(records []JobRecord) error
+	// Remove drops the jobs from the store
+	Remove(jobIDs []string) error
+	Close() error
+}
+
+// JobRecord is the metadata of a job kept in a JobStore, the output is
+// spooled separately as set by Output
+type JobRecord struct {
+	JobID    string        `json:"jobId"`
+	Username string        `json:"username"`
+	Command  []string      `json:"command"`
+	Limits   Limits        `json:"limits"`
+	Timeout  time.Duration `json:"timeout,omitempty"`
+	Output   OutputOptions `json:"output"`
+	State    JobState      `json:"state"`
+	History  []StateChange `json:"history"`
+	Result   JobResult     `json:"result"`
+}
+
+// LogStore is a JobStore appending every change as a json line to a single file.
+// When loading, the last record of a job wins
+type LogStore struct {
+	mutex sync.Mutex
+	dir   string
+	file  *os.File
+}
+
+// logEntry is a line of the LogStore
+type logEntry struct {
+	Job *JobRecord `json:"job,omitempty"`
+}
+
+// NewLogStore opens the log in dir, creating it if needed
+func NewLogStore(dir string) (*LogStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create store folder: %v", err)
+	}
+	file, err := openStoreFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &LogStore{dir: dir, file: file}, nil
+}
+
+// SaveJob appends the record and syncs it to disk
+func (s *LogStore) SaveJob(record JobRecord) error {
+	data, err := encodeEntry(record)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, err := s.file.Write(data); err != nil {
+		return fmt.Errorf("failed to write store: %v", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync store: %v", err)
+	}
+	return nil
+}
+
+// Load replays the log. A torn last line left by a crash is ignored
+func (s *LogStore) Load() ([]JobRecord, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.load()
+}
+
+// load() helper func to replay the log, the caller must hold the mutex
+func (s *LogStore) load() ([]JobRecord, error) {
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read store: %v", err)
+	}
+
+	var order []string
+	jobs := make(map[string]JobRecord)
+	reader := bufio.NewReader(s.file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// a line without a newline was torn by a crash while writing
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read store: %v", err)
+		}
+
+		var entry logEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse store entry: %v", err)
+		}
+		if entry.Job == nil {
+			continue
+		}
+		if _, ok := jobs[entry.Job.JobID]; !ok {
+			order = append(order, entry.Job.JobID)
+		}
+		jobs[entry.Job.JobID] = *entry.Job
+	}
+
+	records := make([]JobRecord, 0, len(order))
+	for _, jobID := range order {
+		records = append(records, jobs[jobID])
+	}
+	return records, nil
+}
+
+// Compact rewrites the log with only the records given. The new log is
+// written next to the old one and renamed over it, so a crash leaves either
+func (s *LogStore) Compact(records []JobRecord) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.rewrite(records)
+}
+
+// Remove rewrites the log without the jobs, like Compact. Changes saved
+// meanwhile wait for it, so none of them is lost with the old log
+func (s *LogStore) Remove(jobIDs []string) error {
+	if len(jobIDs) == 0 {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+	removed := make(map[string]bool, len(jobIDs))
+	for _, jobID := range jobIDs {
+		removed[jobID] = true
+	}
+	kept := make([]JobRecord, 0, len(records))
+	for _, record := range records {
+		if !removed[record.JobID] {
+			kept = append(kept, record)
+		}
+	}
+	return s.rewrite(kept)
+}
+
+// rewrite() helper func to replace the log with records, the caller must hold the mutex
+func (s *LogStore) rewrite(records []JobRecord) error {
+	tmp, err := os.CreateTemp(s.dir, StoreFile+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create store: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	writer := bufio.NewWriter(tmp)
+	for _, record := range records {
+		data, err := encodeEntry(record)
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+		writer.Write(data)
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write store: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync store: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write store: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, StoreFile)); err != nil {
+		return fmt.Errorf("failed to replace store: %v", err)
+	}
+	// the rename is only durable once the folder is synced
+	if dir, err := os.Open(s.dir); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	file, err := openStoreFile(s.dir)
+	if err != nil {
+		return err
+	}
+	s.file.Close()
+	s.file = file
+	return nil
+}
+
+func (s *LogStore) Close() error {
+	return s.file.Close()
+}
+
+// openStoreFile() helper func to open the log in dir for appending, creating it if needed
+func openStoreFile(dir string) (*os.File, error) {
+	file, err := os.OpenFile(filepath.Join(dir, StoreFile), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %v", err)
+	}
+	return file, nil
+}
+
+// encodeEntry() helper func to encode a record as a line of the log
+func encodeEntry(record JobRecord) ([]byte, error) {
+	data, err := json.Marshal(logEntry{Job: &record})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode store entry: %v", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// restoreJob() helper func to rebuild a job from the store. A job that was
+// still active when the server went away can no longer be tracked and is lost
+func restoreJob(record JobRecord, store JobStore) *JobInfo {
+	output, err := OpenOutput(record.JobID, record.Output)
+	if err != nil {
+		log.Printf("job %s: failed to open output: %v", record.JobID, err)
+		output = NewBroadcaster()
+		output.Close()
+	}
+	job := &JobInfo{
+		JobID:      record.JobID,
+		command:    record.Command,
+		output:     output,
+		username:   record.Username,
+		limits:     record.Limits,
+		timeout:    record.Timeout,
+		outputOpts: record.Output,
+		result:     record.Result,
+		state:      record.State,
+		history:    record.History,
+		done:       make(chan struct{}),
+		store:      store,
+	}
+	if len(job.history) == 0 {
+		job.history = []StateChange{{State: job.state}}
+	}
+	close(job.done)
+
+	if !job.state.IsFinal() {
+		job.mutex.Lock()
+		job.result.EndTime = time.Now()
+		job.result.Reason = ReasonLost
+		if err := job.transition(StateLost); err != nil {
+			log.Printf("%v", err)
+		}
+		job.mutex.Unlock()
+		job.save()
+	}
+	return job
+}
+
+// reapLostJobs() helper func to kill what lost jobs left running and remove
+// their cgroups. A server that crashed could not kill them and pid 1 of a pid
+// namespace outlives it, so they are found through their cgroups, all killed
+// first and then given killTimeout together to exit
+func reapLostJobs(cgroup *CGroup, lost []*JobInfo) {
+	var paths []string
+	for _, job := range lost {
+		path := cgroup.JobGroupPath(job.username, job.JobID)
+		if _, err := os.Stat(path); err != nil {
+			// the job never got a cgroup, or it was already removed
+			continue
+		}
+		if err := cgroup.KillJobGroup(path); err != nil {
+			log.Printf("job %s: %v", job.JobID, err)
+		}
+		paths = append(paths, path)
+	}
+	deadline := time.Now().Add(killTimeout)
+	for _, path := range paths {
+		for !cgroup.JobGroupEmpty(path) && time.Now().Before(deadline) {
+			time.Sleep(50 * time.Millisecond)
+		}
+		if err := cgroup.RemoveJobGroup(path); err != nil {
+			log.Printf("failed to remove cgroup of lost job: %v", err)
+		}
+	}
+}
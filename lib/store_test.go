@@ -0,0 +1,255 @@
+package jobworker
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogStoreLoad(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLogStore(dir)
+	assert.Nil(t, err)
+
+	record := JobRecord{JobID: "job1", Username: "alice", Command: []string{"echo", "hi"}, State: StatePending}
+	assert.Nil(t, store.SaveJob(record))
+	record.State = StateSucceeded
+	record.Result = JobResult{ExitCode: 0, Reason: ReasonExited}
+	assert.Nil(t, store.SaveJob(record))
+	assert.Nil(t, store.SaveJob(JobRecord{JobID: "job2", Username: "bob", Command: []string{"true"}}))
+	assert.Nil(t, store.Close())
+
+	// a crash while writing leaves a partial line behind
+	file, err := os.OpenFile(filepath.Join(dir, StoreFile), os.O_WRONLY|os.O_APPEND, 0600)
+	assert.Nil(t, err)
+	file.WriteString(`{"job":{"jobId":"job3"`)
+	file.Close()
+
+	store, err = NewLogStore(dir)
+	assert.Nil(t, err)
+	defer store.Close()
+	stored, err := store.Load()
+	assert.Nil(t, err)
+	assert.Len(t, stored, 2)
+	assert.Equal(t, "job1", stored[0].JobID)
+	assert.Equal(t, StateSucceeded, stored[0].State)
+	assert.Equal(t, ReasonExited, stored[0].Result.Reason)
+	assert.Equal(t, "job2", stored[1].JobID)
+}
+
+func TestLogStoreCompact(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLogStore(dir)
+	assert.Nil(t, err)
+	record := JobRecord{JobID: "job1", Username: "alice", Command: []string{"sleep", "30"}}
+	for _, state := range []JobState{StatePending, StateRunning, StateStopping} {
+		record.State = state
+		assert.Nil(t, store.SaveJob(record))
+	}
+	assert.Nil(t, store.SaveJob(JobRecord{JobID: "job2", Username: "bob", Command: []string{"true"}, State: StateSucceeded}))
+	assert.Nil(t, store.Close())
+
+	// a restart replays the log once and rewrites it with the latest record of each job
+	store, err = NewLogStore(dir)
+	assert.Nil(t, err)
+	defer store.Close()
+	_, err = NewJobWorkerWithStore(store, nil)
+	assert.Nil(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, StoreFile))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, bytes.Count(data, []byte("\n")))
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1, "expected only the log in the store folder")
+
+	// new changes are appended to the compacted log
+	assert.Nil(t, store.SaveJob(JobRecord{JobID: "job3", Username: "alice", Command: []string{"true"}}))
+	stored, err := store.Load()
+	assert.Nil(t, err)
+	assert.Len(t, stored, 3)
+	assert.Equal(t, StateLost, stored[0].State)
+	assert.Equal(t, StateSucceeded, stored[1].State)
+	assert.Equal(t, "job3", stored[2].JobID)
+}
+
+func TestJobWorkerRestoreKillsLostJobs(t *testing.T) {
+	_, cgroup := fakeCGroup(t)
+	lost, err := cgroup.CreateJobGroup("alice", "job1", DefaultLimits())
+	assert.Nil(t, err)
+	finished, err := cgroup.CreateJobGroup("alice", "job2", DefaultLimits())
+	assert.Nil(t, err)
+
+	dir := t.TempDir()
+	store, err := NewLogStore(dir)
+	assert.Nil(t, err)
+	defer store.Close()
+	assert.Nil(t, store.SaveJob(JobRecord{JobID: "job1", Username: "alice", Command: []string{"sleep", "30"}, State: StateRunning}))
+	assert.Nil(t, store.SaveJob(JobRecord{JobID: "job2", Username: "alice", Command: []string{"true"}, State: StateSucceeded}))
+	assert.Nil(t, store.SaveJob(JobRecord{JobID: "job3", Username: "bob", Command: []string{"true"}, State: StatePending}))
+
+	// only the cgroups of lost jobs are killed, a job that never got one is skipped
+	_, err = NewJobWorkerWithStore(store, cgroup)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", readCGroupFile(t, filepath.Join(lost, KillFile)))
+	assert.NoFileExists(t, filepath.Join(finished, KillFile))
+	assert.NoDirExists(t, cgroup.JobGroupPath("bob", "job3"))
+}
+
+func TestJobWorkerRestore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLogStore(dir)
+	assert.Nil(t, err)
+	jw, err := NewJobWorkerWithStore(store, nil)
+	assert.Nil(t, err)
+
+	opts := JobOptions{Username: "alice", Output: OutputOptions{Dir: filepath.Join(dir, "output")}}
+	finished, err := NewJob([]string{"sh", "-c", "echo done; exit 3"}, opts)
+	assert.Nil(t, err)
+	jw.AddJob("alice", finished)
+	finished.Start()
+	for range finished.Subscribe(context.Background()) {
+	}
+
+	running, err := NewJob([]string{"sh", "-c", "echo started; sleep 30"}, opts)
+	assert.Nil(t, err)
+	jw.AddJob("alice", running)
+	go running.Start()
+	defer running.Stop(StopOptions{})
+	for len(running.GetLog()) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// a second worker on the same folder sees what a restarted server would
+	restartedStore, err := NewLogStore(dir)
+	assert.Nil(t, err)
+	defer restartedStore.Close()
+	restarted, err := NewJobWorkerWithStore(restartedStore, nil)
+	assert.Nil(t, err)
+
+	job, err := restarted.LookupJob(finished.JobID)
+	assert.Nil(t, err)
+	assert.Equal(t, "alice", job.Username())
+	assert.Equal(t, StateFailed, job.State())
+	assert.Equal(t, 3, job.Result().ExitCode)
+	history := job.History()
+	assert.Len(t, history, len(finished.History()))
+	for i, change := range finished.History() {
+		assert.Equal(t, change.State, history[i].State)
+		assert.True(t, change.Time.Equal(history[i].Time))
+	}
+	assert.Equal(t, "done\n", string(job.GetLog()[0].Data))
+	assert.NotNil(t, job.Stop(StopOptions{}))
+
+	job, err = restarted.LookupJob(running.JobID)
+	assert.Nil(t, err)
+	assert.Equal(t, StateLost, job.State())
+	assert.Equal(t, ReasonLost, job.Result().Reason)
+	assert.Equal(t, "started\n", string(job.GetLog()[0].Data))
+
+	// the lost state is saved so the job stays lost after another restart
+	stored, err := restartedStore.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, StateLost, stored[1].State)
+}
+
+func TestLogStoreRemove(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLogStore(dir)
+	assert.Nil(t, err)
+	defer store.Close()
+	for _, jobID := range []string{"job1", "job2", "job3"} {
+		assert.Nil(t, store.SaveJob(JobRecord{JobID: jobID, Username: "alice", Command: []string{"true"}, State: StateSucceeded}))
+	}
+
+	assert.Nil(t, store.Remove([]string{"job1", "job3", "missing"}))
+	stored, err := store.Load()
+	assert.Nil(t, err)
+	assert.Len(t, stored, 1)
+	assert.Equal(t, "job2", stored[0].JobID)
+
+	// changes after the removal go to the new log
+	assert.Nil(t, store.SaveJob(JobRecord{JobID: "job4", Username: "bob", Command: []string{"true"}}))
+	stored, err = store.Load()
+	assert.Nil(t, err)
+	assert.Len(t, stored, 2)
+}
+
+func TestJobWorkerPrune(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLogStore(dir)
+	assert.Nil(t, err)
+	defer store.Close()
+	jw, err := NewJobWorkerWithStore(store, nil)
+	assert.Nil(t, err)
+
+	opts := JobOptions{Username: "alice", Output: OutputOptions{Dir: filepath.Join(dir, "output")}}
+	var ended []*JobInfo
+	for i := 0; i < 3; i++ {
+		job, err := NewJob([]string{"echo", "done"}, opts)
+		assert.Nil(t, err)
+		jw.AddJob("alice", job)
+		job.Start()
+		ended = append(ended, job)
+	}
+	running, err := NewJob([]string{"sleep", "30"}, opts)
+	assert.Nil(t, err)
+	jw.AddJob("alice", running)
+	go running.Start()
+	defer running.Stop(StopOptions{})
+	assert.Eventually(t, func() bool { return running.State() == StateRunning }, 5*time.Second, 10*time.Millisecond)
+
+	// nothing is old enough yet
+	dropped, err := jw.Prune(Retention{MaxAge: time.Hour})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, dropped)
+
+	// only the job that ended last is kept with the running one
+	dropped, err = jw.Prune(Retention{MaxJobs: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, dropped)
+	for _, job := range ended[:2] {
+		_, err = jw.LookupJob(job.JobID)
+		assert.NotNil(t, err)
+		assert.NoFileExists(t, filepath.Join(dir, "output", job.JobID+".out"))
+	}
+	_, err = jw.LookupJob(ended[2].JobID)
+	assert.Nil(t, err)
+	assert.FileExists(t, filepath.Join(dir, "output", ended[2].JobID+".out"))
+	stored, err := store.Load()
+	assert.Nil(t, err)
+	assert.Len(t, stored, 2)
+
+	// active jobs are kept however old they are
+	dropped, err = jw.Prune(Retention{MaxAge: time.Nanosecond})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, dropped)
+	_, err = jw.LookupJob(running.JobID)
+	assert.Nil(t, err)
+	stored, err = store.Load()
+	assert.Nil(t, err)
+	assert.Len(t, stored, 1)
+	assert.Equal(t, running.JobID, stored[0].JobID)
+
+	assert.NotNil(t, Retention{MaxAge: -time.Second}.Validate())
+	assert.NotNil(t, Retention{MaxJobs: -1}.Validate())
+}
+
+func TestJobWorkerAddJobSaveFails(t *testing.T) {
+	store, err := NewLogStore(t.TempDir())
+	assert.Nil(t, err)
+	jw, err := NewJobWorkerWithStore(store, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, store.Close())
+
+	// a job that cannot be saved would be lost on the next restart, so it is not added
+	job, err := NewJob([]string{"true"}, JobOptions{Username: "alice"})
+	assert.Nil(t, err)
+	assert.NotNil(t, jw.AddJob("alice", job))
+	_, err = jw.LookupJob(job.JobID)
+	assert.NotNil(t, err)
+}
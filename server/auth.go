@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"strings"
+
+	joblib "github.com/sbui-dev/jobworker/lib"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// roleURIPrefix starts the subject alternative name URIs naming a role, i.e. urn:jobworker:role:admin
+const roleURIPrefix = "urn:jobworker:role:"
+
+// Role is what a caller can do with jobs, from the attributes of their certificate
+type Role int
+
+const (
+	// RoleUser starts jobs and manages their own
+	RoleUser Role = iota
+	// RoleAuditor reads every user's jobs but cannot start or change any
+	RoleAuditor
+	// RoleAdmin reads and manages every user's jobs
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{
+	RoleUser:    "user",
+	RoleAuditor: "auditor",
+	RoleAdmin:   "admin",
+}
+
+func (r Role) String() string {
+	return roleNames[r]
+}
+
+// parseRole() helper func to find the role with a name
+func parseRole(name string) (Role, bool) {
+	for role, roleName := range roleNames {
+		if roleName == name {
+			return role, true
+		}
+	}
+	return RoleUser, false
+}
+
+// action is what a request does with a job
+type action int
+
+const (
+	// actionRead looks at a job: query, logs and list
+	actionRead action = iota
+	// actionControl changes a job: stop, signal, pause, resume, stdin and attach
+	actionControl
+	// actionStart creates a new job for the caller
+	actionStart
+)
+
+var actionNames = map[action]string{
+	actionRead:    "read",
+	actionControl: "control",
+	actionStart:   "start",
+}
+
+func (a action) String() string {
+	return actionNames[a]
+}
+
+// allows() helper func to check if the role can take the action on a job, own
+// when the caller owns the job. This is the only place deciding who can do what
+func (r Role) allows(act action, own bool) bool {
+	switch r {
+	case RoleAdmin:
+		return true
+	case RoleAuditor:
+		return act == actionRead
+	default:
+		return own
+	}
+}
+
+// Principal is who sent a request, put in the request's context by the interceptors
+// Username: the common name of their certificate
+// Role: the highest role their certificate names, RoleUser when it names none
+type Principal struct {
+	Username string
+	Role     Role
+}
+
+// principalKey is the context key of the Principal
+type principalKey struct{}
+
+// principalFromCertificate() helper func to identify the sender of a request
+// from their verified client certificate
+func principalFromCertificate(ctx context.Context) (Principal, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return Principal{}, status.Error(codes.Unauthenticated, "no peer found")
+	}
+
+	tlsAuth, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return Principal{}, status.Error(codes.Unauthenticated, "unexpected peer transport credentials")
+	}
+
+	if len(tlsAuth.State.VerifiedChains) == 0 || len(tlsAuth.State.VerifiedChains[0]) == 0 {
+		return Principal{}, status.Error(codes.Unauthenticated, "could not verify peer certificate")
+	}
+
+	cert := tlsAuth.State.VerifiedChains[0][0]
+	return Principal{Username: cert.Subject.CommonName, Role: roleFromCertificate(cert)}, nil
+}
+
+// principalFromContext() helper func to get the Principal the interceptors put
+// in the context. A request that did not go through them is refused
+func principalFromContext(ctx context.Context) (Principal, error) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	if !ok {
+		return Principal{}, status.Error(codes.Unauthenticated, "request was not authenticated")
+	}
+	return principal, nil
+}
+
+// unaryAuthInterceptor() helper func to authenticate every unary rpc before its
+// handler runs, so new rpcs cannot forget to
+func unaryAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	principal, err := principalFromCertificate(ctx)
+	if err != nil {
+		log.Printf("%s: %v", info.FullMethod, err)
+		return nil, err
+	}
+	log.Printf("%s: %s (%s)", info.FullMethod, principal.Username, principal.Role)
+	return handler(context.WithValue(ctx, principalKey{}, principal), req)
+}
+
+// authenticatedStream is a server stream whose context holds the Principal
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context with the Principal
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// streamAuthInterceptor() helper func to authenticate every streaming rpc
+// before its handler runs, like unaryAuthInterceptor
+func streamAuthInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	principal, err := principalFromCertificate(stream.Context())
+	if err != nil {
+		log.Printf("%s: %v", info.FullMethod, err)
+		return err
+	}
+	log.Printf("%s: %s (%s)", info.FullMethod, principal.Username, principal.Role)
+	ctx := context.WithValue(stream.Context(), principalKey{}, principal)
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+// roleFromCertificate() helper func to get the highest role named by the
+// organizational units or the role URIs of a certificate. Other values are ignored
+func roleFromCertificate(cert *x509.Certificate) Role {
+	names := append([]string{}, cert.Subject.OrganizationalUnit...)
+	for _, uri := range cert.URIs {
+		if name := uri.String(); strings.HasPrefix(name, roleURIPrefix) {
+			names = append(names, strings.TrimPrefix(name, roleURIPrefix))
+		}
+	}
+
+	highest := RoleUser
+	for _, name := range names {
+		if role, ok := parseRole(name); ok && role > highest {
+			highest = role
+		}
+	}
+	return highest
+}
+
+// authorize() helper func to get the caller and check they can take an
+// action that is not about an existing job
+func (w *workerServer) authorize(ctx context.Context, act action) (Principal, error) {
+	principal, err := principalFromContext(ctx)
+	if err != nil {
+		return principal, err
+	}
+	if !principal.Role.allows(act, true) {
+		return principal, status.Error(codes.PermissionDenied, fmt.Sprintf("%s has the %s role and cannot %s jobs", principal.Username, principal.Role, act))
+	}
+	return principal, nil
+}
+
+// authorizeJob() helper func to get the caller, find a job and check they
+// can take the action on it. Jobs of other users the caller cannot read are
+// reported as missing so their ids are not revealed
+func (w *workerServer) authorizeJob(ctx context.Context, jobID string, act action) (*joblib.JobInfo, error) {
+	principal, err := principalFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	job, err := w.JobWorker.LookupJob(jobID)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	own := job.Username() == principal.Username
+	if !principal.Role.allows(act, own) {
+		if !own && !principal.Role.allows(actionRead, own) {
+			return nil, status.Error(codes.NotFound, fmt.Sprintf("cannot find a job with id %s", jobID))
+		}
+		return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("%s has the %s role and cannot %s job %s", principal.Username, principal.Role, act, jobID))
+	}
+	return job, nil
+}
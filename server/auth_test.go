@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+
+	worker "github.com/sbui-dev/jobworker/data/proto"
+	joblib "github.com/sbui-dev/jobworker/lib"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// principalContext helper func to get the context the interceptors give a request of the principal
+func principalContext(username string, role Role) context.Context {
+	return context.WithValue(context.Background(), principalKey{}, Principal{Username: username, Role: role})
+}
+
+// testServer helper func to set up a server holding one job for alice and one for bob
+func testServer(t *testing.T) (*workerServer, *joblib.JobInfo, *joblib.JobInfo) {
+	w := &workerServer{JobWorker: joblib.NewJobWorker()}
+	var jobs []*joblib.JobInfo
+	for _, username := range []string{"alice", "bob"} {
+		job, err := joblib.NewJob([]string{"true"}, joblib.JobOptions{Username: username})
+		assert.Nil(t, err)
+		w.JobWorker.AddJob(username, job)
+		jobs = append(jobs, job)
+	}
+	return w, jobs[0], jobs[1]
+}
+
+func TestRoleFromCertificate(t *testing.T) {
+	uri := func(value string) *url.URL {
+		parsed, err := url.Parse(value)
+		assert.Nil(t, err)
+		return parsed
+	}
+	tests := []struct {
+		name  string
+		units []string
+		uris  []*url.URL
+		role  Role
+	}{
+		{name: "no role", role: RoleUser},
+		{name: "unknown unit", units: []string{"engineering"}, role: RoleUser},
+		{name: "auditor unit", units: []string{"auditor"}, role: RoleAuditor},
+		{name: "admin unit", units: []string{"admin"}, role: RoleAdmin},
+		{name: "highest unit wins", units: []string{"admin", "auditor"}, role: RoleAdmin},
+		{name: "auditor uri", uris: []*url.URL{uri("urn:jobworker:role:auditor")}, role: RoleAuditor},
+		{name: "admin uri", uris: []*url.URL{uri("urn:jobworker:role:admin")}, role: RoleAdmin},
+		{name: "uri of another prefix", uris: []*url.URL{uri("urn:other:role:admin")}, role: RoleUser},
+		{name: "unknown role uri", uris: []*url.URL{uri("urn:jobworker:role:root")}, role: RoleUser},
+		{name: "uri above unit", units: []string{"auditor"}, uris: []*url.URL{uri("urn:jobworker:role:admin")}, role: RoleAdmin},
+		{name: "unit above uri", units: []string{"admin"}, uris: []*url.URL{uri("urn:jobworker:role:auditor")}, role: RoleAdmin},
+	}
+	for _, test := range tests {
+		cert := &x509.Certificate{
+			Subject: pkix.Name{CommonName: "alice", OrganizationalUnit: test.units},
+			URIs:    test.uris,
+		}
+		assert.Equal(t, test.role, roleFromCertificate(cert), test.name)
+	}
+}
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role    Role
+		act     action
+		own     bool
+		allowed bool
+	}{
+		{RoleUser, actionRead, true, true},
+		{RoleUser, actionControl, true, true},
+		{RoleUser, actionStart, true, true},
+		{RoleUser, actionRead, false, false},
+		{RoleUser, actionControl, false, false},
+		{RoleAuditor, actionRead, true, true},
+		{RoleAuditor, actionRead, false, true},
+		{RoleAuditor, actionControl, true, false},
+		{RoleAuditor, actionControl, false, false},
+		{RoleAuditor, actionStart, true, false},
+		{RoleAdmin, actionRead, false, true},
+		{RoleAdmin, actionControl, false, true},
+		{RoleAdmin, actionStart, true, true},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.allowed, test.role.allows(test.act, test.own), "%s %s own=%t", test.role, test.act, test.own)
+	}
+}
+
+func TestAuthorizeJob(t *testing.T) {
+	w, aliceJob, bobJob := testServer(t)
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		jobID  string
+		act    action
+		code   codes.Code
+		reason string
+	}{
+		{name: "owner reads", ctx: principalContext("alice", RoleUser), jobID: aliceJob.JobID, act: actionRead, code: codes.OK},
+		{name: "owner controls", ctx: principalContext("alice", RoleUser), jobID: aliceJob.JobID, act: actionControl, code: codes.OK},
+		{name: "user reads another's job", ctx: principalContext("alice", RoleUser), jobID: bobJob.JobID, act: actionRead, code: codes.NotFound},
+		{name: "user controls another's job", ctx: principalContext("alice", RoleUser), jobID: bobJob.JobID, act: actionControl, code: codes.NotFound},
+		{name: "missing job", ctx: principalContext("alice", RoleUser), jobID: "missing", act: actionRead, code: codes.NotFound},
+		{name: "auditor reads", ctx: principalContext("carl", RoleAuditor), jobID: bobJob.JobID, act: actionRead, code: codes.OK},
+		{name: "auditor controls", ctx: principalContext("carl", RoleAuditor), jobID: bobJob.JobID, act: actionControl, code: codes.PermissionDenied, reason: "carl has the auditor role and cannot control job"},
+		{name: "auditor controls own job", ctx: principalContext("alice", RoleAuditor), jobID: aliceJob.JobID, act: actionControl, code: codes.PermissionDenied},
+		{name: "admin controls", ctx: principalContext("carl", RoleAdmin), jobID: bobJob.JobID, act: actionControl, code: codes.OK},
+		{name: "unauthenticated", ctx: context.Background(), jobID: aliceJob.JobID, act: actionRead, code: codes.Unauthenticated},
+	}
+	for _, test := range tests {
+		job, err := w.authorizeJob(test.ctx, test.jobID, test.act)
+		assert.Equal(t, test.code, status.Code(err), test.name)
+		if test.code == codes.OK {
+			assert.Equal(t, test.jobID, job.JobID, test.name)
+			continue
+		}
+		assert.Nil(t, job, test.name)
+		if test.reason != "" {
+			assert.Contains(t, status.Convert(err).Message(), test.reason, test.name)
+		}
+	}
+
+	// a job of another user is as missing as one that does not exist
+	_, hidden := w.authorizeJob(principalContext("alice", RoleUser), bobJob.JobID, actionRead)
+	_, missing := w.authorizeJob(principalContext("alice", RoleUser), "missing", actionRead)
+	assert.Equal(t, "cannot find a job with id "+bobJob.JobID, status.Convert(hidden).Message())
+	assert.Equal(t, "cannot find a job with id missing", status.Convert(missing).Message())
+}
+
+func TestJobListAllUsers(t *testing.T) {
+	w, aliceJob, bobJob := testServer(t)
+
+	tests := []struct {
+		name     string
+		username string
+		role     Role
+		allUsers bool
+		code     codes.Code
+		jobs     []string
+	}{
+		{name: "user lists own jobs", username: "alice", role: RoleUser, code: codes.OK, jobs: []string{aliceJob.JobID}},
+		{name: "user lists all jobs", username: "alice", role: RoleUser, allUsers: true, code: codes.PermissionDenied},
+		{name: "auditor lists own jobs", username: "bob", role: RoleAuditor, code: codes.OK, jobs: []string{bobJob.JobID}},
+		{name: "auditor lists all jobs", username: "carl", role: RoleAuditor, allUsers: true, code: codes.OK, jobs: []string{aliceJob.JobID, bobJob.JobID}},
+		{name: "admin lists all jobs", username: "carl", role: RoleAdmin, allUsers: true, code: codes.OK, jobs: []string{aliceJob.JobID, bobJob.JobID}},
+	}
+	for _, test := range tests {
+		resp, err := w.JobList(principalContext(test.username, test.role), &worker.WorkerListRequest{AllUsers: test.allUsers})
+		assert.Equal(t, test.code, status.Code(err), test.name)
+		if test.code != codes.OK {
+			continue
+		}
+		var jobs []string
+		for _, job := range resp.Jobs {
+			jobs = append(jobs, job.JobId)
+		}
+		assert.ElementsMatch(t, test.jobs, jobs, test.name)
+	}
+}
@@ -4,11 +4,20 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"math"
 	"net"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
 
 	worker "github.com/sbui-dev/jobworker/data/proto"
 	joblib "github.com/sbui-dev/jobworker/lib"
@@ -16,8 +25,8 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
@@ -27,55 +36,269 @@ const (
 	serverKeyPath  = "server_key.pem"
 )
 
-var port = flag.Int("port", 50005, "the port to serve on")
+var (
+	port        = flag.Int("port", 50005, "the port to serve on")
+	cgroupRoot  = flag.String("cgroup", joblib.JobFolder, "cgroup v2 folder jobs are placed in, empty to disable")
+	limitsPath  = flag.String("limits", "", "json file with the default and maximum job limits")
+	nsPath      = flag.String("namespaces", "", "json file with the namespaces jobs must run in, by default and per user")
+	commandPath = flag.String("commands", "", "json file with the rules of which commands users can run, reloaded on SIGHUP, empty to allow any command")
+	seccompPath = flag.String("seccomp", "", "json file with the minimum seccomp profile of jobs, by default and per user")
+	usersPath   = flag.String("users", "", "json file mapping certificate common names to the uid, gid and groups their jobs run as, empty to run jobs as the server's user")
+	imagesDir   = flag.String("images", "", "folder of root filesystem images jobs can run in, one extracted image per subfolder, needs --data-dir, empty to disable")
+	dataDir     = flag.String("data-dir", "", "folder jobs and their output are saved in to survive restarts, empty to keep them in memory")
+	outputMax   = flag.Int64("output-max", 64*1024*1024, "most bytes of output kept per job, 0 for no limit")
+	outputMode  = flag.String("output-policy", string(joblib.PolicyRotate), "what to do with output past output-max: rotate keeps the latest, truncate keeps the first")
+	keepFor     = flag.Duration("keep-for", 0, "how long jobs are kept with their output after they ended, i.e. 720h, 0 to keep them forever")
+	keepJobs    = flag.Int("keep-jobs", 0, "most ended jobs kept with their output, the ones that ended last, 0 for no limit")
+)
+
+// pruneInterval is how often jobs the retention does not keep are dropped
+const pruneInterval = time.Minute
+
+var terminationReasons = map[joblib.TerminationReason]worker.TerminationReason{
+	joblib.ReasonNone:        worker.TerminationReason_TERMINATION_REASON_NONE,
+	joblib.ReasonExited:      worker.TerminationReason_TERMINATION_REASON_EXITED,
+	joblib.ReasonSignaled:    worker.TerminationReason_TERMINATION_REASON_SIGNALED,
+	joblib.ReasonStopped:     worker.TerminationReason_TERMINATION_REASON_STOPPED,
+	joblib.ReasonOOMKilled:   worker.TerminationReason_TERMINATION_REASON_OOM_KILLED,
+	joblib.ReasonTimedOut:    worker.TerminationReason_TERMINATION_REASON_TIMED_OUT,
+	joblib.ReasonStartFailed: worker.TerminationReason_TERMINATION_REASON_START_FAILED,
+	joblib.ReasonLost:        worker.TerminationReason_TERMINATION_REASON_LOST,
+}
+
+var outputStreams = map[joblib.Stream]worker.OutputStream{
+	joblib.Stdout: worker.OutputStream_OUTPUT_STREAM_STDOUT,
+	joblib.Stderr: worker.OutputStream_OUTPUT_STREAM_STDERR,
+}
+
+var jobStates = map[joblib.JobState]worker.JobState{
+	joblib.StatePending:   worker.JobState_JOB_STATE_PENDING,
+	joblib.StateRunning:   worker.JobState_JOB_STATE_RUNNING,
+	joblib.StateSucceeded: worker.JobState_JOB_STATE_SUCCEEDED,
+	joblib.StateFailed:    worker.JobState_JOB_STATE_FAILED,
+	joblib.StateStopping:  worker.JobState_JOB_STATE_STOPPING,
+	joblib.StateStopped:   worker.JobState_JOB_STATE_STOPPED,
+	joblib.StateKilled:    worker.JobState_JOB_STATE_KILLED,
+	joblib.StateLost:      worker.JobState_JOB_STATE_LOST,
+	joblib.StatePaused:    worker.JobState_JOB_STATE_PAUSED,
+}
 
 type workerServer struct {
-	JobWorker *joblib.JobWorker
+	JobWorker   *joblib.JobWorker
+	CGroup      *joblib.CGroup
+	LimitPolicy joblib.LimitPolicy
+	Output      joblib.OutputOptions
+	// Users: local users jobs run as, nil to run them as the server's user
+	Users *joblib.UserMap
+	// NamespacePolicy: namespaces jobs run in on top of the requested ones
+	NamespacePolicy joblib.NamespacePolicy
+	// SeccompPolicy: minimum seccomp profile of jobs
+	SeccompPolicy joblib.SeccompPolicy
+	// Images: root filesystems jobs can run in, nil when disabled
+	Images *joblib.Images
+	// Commands: rules of which commands users can run
+	Commands *commandPolicy
 	worker.UnimplementedWorkerServer
 }
 
-func getUserFromCertificate(ctx context.Context) (string, error) {
-	fmt.Println("getting user name")
-	p, ok := peer.FromContext(ctx)
-	if !ok {
-		return "", status.Error(codes.Unauthenticated, "no peer found")
+// commandPolicy holds the command policy loaded from path, it is replaced
+// while jobs are being started when the server reloads it
+type commandPolicy struct {
+	mutex  sync.RWMutex
+	path   string
+	policy joblib.CommandPolicy
+}
+
+// get returns the current policy
+func (c *commandPolicy) get() joblib.CommandPolicy {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.policy
+}
+
+// reload reads the policy file again, the current policy is kept when it is invalid
+func (c *commandPolicy) reload() error {
+	var policy joblib.CommandPolicy
+	if c.path != "" {
+		data, err := os.ReadFile(c.path)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &policy); err != nil {
+			return err
+		}
+		if err := policy.Validate(); err != nil {
+			return err
+		}
 	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.policy = policy
+	return nil
+}
 
-	tlsAuth, ok := p.AuthInfo.(credentials.TLSInfo)
-	if !ok {
-		return "", status.Error(codes.Unauthenticated, "unexpected peer transport credentials")
+// reloadOnHangup helper func to reload the command policy every time the server gets SIGHUP
+func reloadOnHangup(commands *commandPolicy) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	for range hangup {
+		if err := commands.reload(); err != nil {
+			log.Printf("failed to reload commands %q, keeping the previous rules: %v", commands.path, err)
+			continue
+		}
+		log.Printf("reloaded commands %q", commands.path)
 	}
+}
 
-	if len(tlsAuth.State.VerifiedChains) == 0 || len(tlsAuth.State.VerifiedChains[0]) == 0 {
-		return "", status.Error(codes.Unauthenticated, "could not verify peer certificate")
+// pruneJobs helper func to drop the jobs the retention does not keep
+func pruneJobs(jw *joblib.JobWorker, retention joblib.Retention) {
+	dropped, err := jw.Prune(retention)
+	if err != nil {
+		log.Printf("failed to drop old jobs: %v", err)
+	} else if dropped > 0 {
+		log.Printf("dropped %d old jobs", dropped)
 	}
+}
 
-	username := tlsAuth.State.VerifiedChains[0][0].Subject.CommonName
-	fmt.Printf("user name is %s\n", username)
-	return username, nil
+// limitsFromRequest helper func to convert the requested proto limits
+func limitsFromRequest(req *worker.ResourceLimits) joblib.Limits {
+	if req == nil {
+		return joblib.Limits{}
+	}
+	limits := joblib.Limits{
+		CPUQuota:  req.CpuQuota,
+		CPUPeriod: req.CpuPeriod,
+		Memory:    req.MemoryBytes,
+		Pids:      req.PidsMax,
+	}
+	for _, io := range req.Io {
+		limits.IO = append(limits.IO, joblib.IOLimit{
+			Device:    io.Device,
+			ReadBPS:   io.ReadBps,
+			WriteBPS:  io.WriteBps,
+			ReadIOPS:  io.ReadIops,
+			WriteIOPS: io.WriteIops,
+		})
+	}
+	return limits
 }
 
-func (w *workerServer) JobStart(req *worker.WorkerStartRequest, stream worker.Worker_JobStartServer) error {
-	fmt.Printf("Creating new job: %s\n", req.Command)
+// loadLimitPolicy helper func to read the administrator's limit policy
+func loadLimitPolicy(path string) (joblib.LimitPolicy, error) {
+	policy := joblib.DefaultLimitPolicy()
+	if path == "" {
+		return policy, nil
+	}
 
-	ctx := stream.Context()
-	username, err := getUserFromCertificate(ctx)
+	data, err := os.ReadFile(path)
 	if err != nil {
-		fmt.Printf("%v", err)
-		return err
+		return policy, err
+	}
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return policy, err
+	}
+	if err := policy.Validate(); err != nil {
+		return policy, err
+	}
+	// io.max rejects devices the host does not have, which would fail every job
+	if err := policy.CheckDevices(joblib.BlockDevicesFolder); err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
+
+// startJob helper func to validate a start request, create the job for the user and run it
+func (w *workerServer) startJob(username string, req *worker.WorkerStartRequest) (*joblib.JobInfo, error) {
+	requested := limitsFromRequest(req.Limits)
+	if err := requested.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	limits, err := w.LimitPolicy.Resolve(username, requested)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	if req.TimeoutSeconds < 0 {
+		return nil, status.Error(codes.InvalidArgument, "timeout cannot be negative")
 	}
 
-	newJob, err := joblib.NewJob(req.Command)
+	size, err := terminalSize(req.TerminalSize)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	process, err := processFromRequest(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	process.Namespaces = w.NamespacePolicy.Resolve(username, namespacesFromRequest(req.Namespaces))
+	process.Seccomp = w.SeccompPolicy.Resolve(username, process.Seccomp)
+	if req.Rootfs != "" {
+		if w.Images == nil {
+			return nil, status.Error(codes.FailedPrecondition, "root filesystem images are disabled on this server")
+		}
+		process.RootFS, err = w.Images.RootFS(req.Rootfs)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if w.Users != nil {
+		process.Credential, err = w.Users.Lookup(username)
+		if err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+	}
+	if w.Commands != nil && len(req.Command) > 0 {
+		path := joblib.ResolveCommand(req.Command[0], process)
+		if !filepath.IsAbs(path) {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("cannot find command %s", path))
+		}
+		if err := w.Commands.get().Check(username, path, req.Command[1:], process.Env, limits); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		// the job runs the file that was checked, whatever its command names later
+		process.Path = path
+	}
+
+	newJob, err := joblib.NewJob(req.Command, joblib.JobOptions{
+		Username:     username,
+		CGroup:       w.CGroup,
+		Limits:       limits,
+		Timeout:      time.Duration(req.TimeoutSeconds) * time.Second,
+		Output:       w.Output,
+		Stdin:        req.Stdin,
+		TTY:          req.Tty,
+		TerminalSize: size,
+		Process:      process,
+	})
 	if err != nil {
 		log.Println(err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	// add to array
-	w.JobWorker.AddJob(username, newJob)
+	if err := w.JobWorker.AddJob(username, newJob); err != nil {
+		// stopping the pending job releases its output and stdin
+		newJob.Stop(joblib.StopOptions{})
+		return nil, status.Error(codes.Internal, err.Error())
+	}
 
-	fmt.Println("Job starting")
 	go newJob.Start()
-	outChan := newJob.GetOutputChannel()
+	return newJob, nil
+}
+
+func (w *workerServer) JobStart(req *worker.WorkerStartRequest, stream worker.Worker_JobStartServer) error {
+	log.Printf("Creating new job: %s\n", req.Command)
+
+	ctx := stream.Context()
+	principal, err := w.authorize(ctx, actionStart)
+	if err != nil {
+		return err
+	}
+
+	newJob, err := w.startJob(principal.Username, req)
+	if err != nil {
+		return err
+	}
+	outChan := newJob.Subscribe(ctx)
 
 	for {
 		select {
@@ -87,57 +310,495 @@ func (w *workerServer) JobStart(req *worker.WorkerStartRequest, stream worker.Wo
 				log.Printf("channel closed")
 				return nil
 			}
-			fmt.Println("sending response")
-			err = stream.Send(&worker.WorkerStartResponse{JobId: newJob.JobID, Log: out})
+			err = stream.Send(&worker.WorkerStartResponse{
+				JobId:  newJob.JobID,
+				Data:   out.Data,
+				Stream: outputStreams[out.Stream],
+				Time:   timestamppb.New(out.Time),
+				Offset: out.Offset,
+			})
 			if err != nil {
-				fmt.Println(err.Error())
+				log.Printf("failed to send output of job %s: %v", newJob.JobID, err)
 				return err
 			}
 		}
 	}
 }
 
+func (w *workerServer) JobSubmit(ctx context.Context, req *worker.WorkerStartRequest) (*worker.WorkerSubmitResponse, error) {
+	log.Printf("Submitting new job: %s\n", req.Command)
+	principal, err := w.authorize(ctx, actionStart)
+	if err != nil {
+		return nil, err
+	}
+
+	newJob, err := w.startJob(principal.Username, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &worker.WorkerSubmitResponse{JobId: newJob.JobID, State: jobStates[newJob.State()]}, nil
+}
+
 func (w *workerServer) JobStop(ctx context.Context, req *worker.WorkerStopRequest) (*worker.WorkerStopResponse, error) {
 	log.Printf("Stop job: %s\n", req.JobId)
-	username, err := getUserFromCertificate(ctx)
+	myJob, err := w.authorizeJob(ctx, req.JobId, actionControl)
 	if err != nil {
-		fmt.Printf("%v", err)
 		return nil, err
 	}
 
-	myJob, err := w.JobWorker.FindJob(username, req.JobId)
+	if req.TimeoutSeconds < 0 {
+		return nil, status.Error(codes.InvalidArgument, "timeout cannot be negative")
+	}
+	// the rpc waits for the job to exit, so callers cannot hold it open for long
+	if req.TimeoutSeconds > int64(joblib.MaxStopTimeout/time.Second) {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("timeout cannot be longer than %v", joblib.MaxStopTimeout))
+	}
+	opts := joblib.StopOptions{Timeout: time.Duration(req.TimeoutSeconds) * time.Second}
+	if req.Signal != "" {
+		opts.Signal, err = joblib.ParseSignal(req.Signal)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	if err := myJob.Stop(opts); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return &worker.WorkerStopResponse{}, nil
+
+}
+
+func (w *workerServer) JobStdin(stream worker.Worker_JobStdinServer) error {
+	ctx := stream.Context()
+	req, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "missing job id")
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("Stdin job: %s\n", req.JobId)
+	myJob, err := w.authorizeJob(ctx, req.JobId, actionControl)
+	if err != nil {
+		return err
+	}
+
+	var written int64
+	for {
+		if len(req.Data) > 0 {
+			if err := myJob.WriteStdin(req.Data); err != nil {
+				return status.Error(codes.FailedPrecondition, err.Error())
+			}
+			written += int64(len(req.Data))
+		}
+		if req.Eof {
+			if err := myJob.CloseStdin(); err != nil {
+				return status.Error(codes.FailedPrecondition, err.Error())
+			}
+			break
+		}
+
+		req, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return stream.SendAndClose(&worker.WorkerStdinResponse{BytesWritten: written})
+}
+
+// JobAttach connects the caller to the terminal of a job started with tty. Keystrokes
+// and resizes are forwarded until the caller ends its side of the stream, the terminal
+// output is sent from the beginning and the stream ends once the job has ended
+func (w *workerServer) JobAttach(stream worker.Worker_JobAttachServer) error {
+	ctx := stream.Context()
+	req, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "missing job id")
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("Attach job: %s\n", req.JobId)
+	myJob, err := w.authorizeJob(ctx, req.JobId, actionControl)
+	if err != nil {
+		return err
+	}
+	if !myJob.TTY() {
+		return status.Error(codes.FailedPrecondition, fmt.Sprintf("job %s was not started with a tty", req.JobId))
+	}
+	if err := attachInput(myJob, req); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			if err := attachInput(myJob, req); err != nil {
+				log.Printf("%v", err)
+				return
+			}
+		}
+	}()
+
+	output, err := myJob.Logs(ctx, joblib.LogOptions{Follow: true})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	for chunk := range output {
+		if err := stream.Send(&worker.WorkerAttachResponse{Data: chunk.Data, Offset: chunk.Offset}); err != nil {
+			return err
+		}
+	}
+	// the result is final once the stream ends so the caller can query the exit code
+	select {
+	case <-myJob.Done():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// attachInput() helper func to apply a JobAttach message to the job's terminal
+func attachInput(job *joblib.JobInfo, req *worker.WorkerAttachRequest) error {
+	if req.Resize != nil {
+		size, err := terminalSize(req.Resize)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if err := job.Resize(size); err != nil {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+	if len(req.Data) > 0 {
+		if err := job.WriteStdin(req.Data); err != nil {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+	return nil
+}
+
+// processFromRequest() helper func to read and validate the process options of a start request
+func processFromRequest(req *worker.WorkerStartRequest) (joblib.ProcessOptions, error) {
+	process := joblib.ProcessOptions{
+		Env:      req.Env,
+		CleanEnv: req.CleanEnv,
+		Dir:      req.Cwd,
+		Seccomp:  joblib.SeccompProfile(req.Seccomp),
+	}
+	if req.Umask != "" {
+		mask, err := strconv.ParseUint(req.Umask, 8, 32)
+		if err != nil {
+			return process, fmt.Errorf("invalid umask %q, expected an octal number", req.Umask)
+		}
+		umask := uint32(mask)
+		process.Umask = &umask
+	}
+	return process, process.Validate()
+}
+
+// namespacesFromRequest() helper func to convert the requested proto namespaces
+func namespacesFromRequest(req *worker.Namespaces) joblib.Namespaces {
+	if req == nil {
+		return joblib.Namespaces{}
+	}
+	return joblib.Namespaces{
+		PID:   req.Pid,
+		Mount: req.Mount,
+		UTS:   req.Uts,
+		IPC:   req.Ipc,
+		Net:   req.Net,
+	}
+}
+
+// terminalSize() helper func to convert a requested terminal size, nil leaves the size unchanged
+func terminalSize(size *worker.TerminalSize) (joblib.TerminalSize, error) {
+	if size == nil {
+		return joblib.TerminalSize{}, nil
+	}
+	if size.Rows > math.MaxUint16 || size.Cols > math.MaxUint16 {
+		return joblib.TerminalSize{}, fmt.Errorf("terminal size %dx%d is too large", size.Cols, size.Rows)
+	}
+	return joblib.TerminalSize{Rows: uint16(size.Rows), Cols: uint16(size.Cols)}, nil
+}
+
+func (w *workerServer) JobSignal(ctx context.Context, req *worker.WorkerSignalRequest) (*worker.WorkerSignalResponse, error) {
+	log.Printf("Signal job: %s %s\n", req.JobId, req.Signal)
+	myJob, err := w.authorizeJob(ctx, req.JobId, actionControl)
 	if err != nil {
 		return nil, err
 	}
 
-	if myJob.IsRunning() {
-		myJob.Stop()
+	sig, err := joblib.ParseSignal(req.Signal)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := myJob.Signal(sig); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
 	}
 
-	return &worker.WorkerStopResponse{}, nil
+	return &worker.WorkerSignalResponse{}, nil
+}
 
+func (w *workerServer) JobPause(ctx context.Context, req *worker.WorkerPauseRequest) (*worker.WorkerPauseResponse, error) {
+	log.Printf("Pause job: %s\n", req.JobId)
+	myJob, err := w.authorizeJob(ctx, req.JobId, actionControl)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := myJob.Pause(); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return &worker.WorkerPauseResponse{}, nil
+}
+
+func (w *workerServer) JobResume(ctx context.Context, req *worker.WorkerResumeRequest) (*worker.WorkerResumeResponse, error) {
+	log.Printf("Resume job: %s\n", req.JobId)
+	myJob, err := w.authorizeJob(ctx, req.JobId, actionControl)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := myJob.Resume(); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return &worker.WorkerResumeResponse{}, nil
 }
 
 func (w *workerServer) JobQuery(ctx context.Context, req *worker.WorkerQueryRequest) (*worker.WorkerQueryResponse, error) {
-	fmt.Printf("Query job: %s\n", req.JobId)
-	username, err := getUserFromCertificate(ctx)
+	log.Printf("Query job: %s\n", req.JobId)
+	myJob, err := w.authorizeJob(ctx, req.JobId, actionRead)
 	if err != nil {
-		fmt.Printf("%v", err)
 		return nil, err
 	}
 
-	myJob, err := w.JobWorker.FindJob(username, req.JobId)
+	state := myJob.State()
+
+	result := myJob.Result()
+	resp := &worker.WorkerQueryResponse{
+		JobId:    myJob.JobID,
+		Status:   state.String(),
+		State:    jobStates[state],
+		ExitCode: int32(result.ExitCode),
+		Signal:   result.Signal,
+		Reason:   terminationReasons[result.Reason],
+	}
+	if !result.StartTime.IsZero() {
+		resp.StartTime = timestamppb.New(result.StartTime)
+	}
+	if !result.EndTime.IsZero() {
+		resp.EndTime = timestamppb.New(result.EndTime)
+	}
+	for _, change := range myJob.History() {
+		resp.History = append(resp.History, &worker.StateChange{
+			State: jobStates[change.State],
+			Time:  timestamppb.New(change.Time),
+		})
+	}
+
+	return resp, nil
+
+}
+
+func (w *workerServer) JobLogs(req *worker.WorkerLogsRequest, stream worker.Worker_JobLogsServer) error {
+	log.Printf("Logs job: %s\n", req.JobId)
+	ctx := stream.Context()
+	myJob, err := w.authorizeJob(ctx, req.JobId, actionRead)
+	if err != nil {
+		return err
+	}
+
+	outChan, err := myJob.Logs(ctx, joblib.LogOptions{
+		Offset: req.Offset,
+		Tail:   int(req.Tail),
+		Follow: req.Follow,
+	})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	for out := range outChan {
+		err = stream.Send(&worker.WorkerLogsResponse{
+			JobId:  myJob.JobID,
+			Stream: outputStreams[out.Stream],
+			Time:   timestamppb.New(out.Time),
+			Data:   out.Data,
+			Offset: out.Offset,
+		})
+		if err != nil {
+			log.Printf("failed to send logs of job %s: %v", myJob.JobID, err)
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// listFilter helper func to convert the filters of a list request
+func listFilter(req *worker.WorkerListRequest) (joblib.ListFilter, error) {
+	filter := joblib.ListFilter{Command: req.Command}
+	for _, requested := range req.States {
+		found := false
+		for state, protoState := range jobStates {
+			if protoState == requested {
+				filter.States = append(filter.States, state)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return filter, fmt.Errorf("unknown job state %s", requested)
+		}
+	}
+	if req.Since != nil {
+		filter.Since = req.Since.AsTime()
+	}
+	if req.Until != nil {
+		filter.Until = req.Until.AsTime()
+	}
+	return filter, nil
+}
+
+func (w *workerServer) JobList(ctx context.Context, req *worker.WorkerListRequest) (*worker.WorkerListResponse, error) {
+	principal, err := w.authorize(ctx, actionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := listFilter(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var jobs []*joblib.JobInfo
+	var nextToken string
+	if req.AllUsers {
+		if !principal.Role.allows(actionRead, false) {
+			return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("%s has the %s role and cannot list other users' jobs", principal.Username, principal.Role))
+		}
+		jobs, nextToken, err = w.JobWorker.ListAllJobs(filter, int(req.PageSize), req.PageToken)
+	} else {
+		jobs, nextToken, err = w.JobWorker.ListJobs(principal.Username, filter, int(req.PageSize), req.PageToken)
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &worker.WorkerListResponse{NextPageToken: nextToken}
+	for _, job := range jobs {
+		result := job.Result()
+		summary := &worker.JobSummary{
+			JobId:      job.JobID,
+			Username:   job.Username(),
+			Command:    job.Command(),
+			State:      jobStates[job.State()],
+			CreateTime: timestamppb.New(job.CreateTime()),
+			ExitCode:   int32(result.ExitCode),
+		}
+		if !result.StartTime.IsZero() {
+			summary.StartTime = timestamppb.New(result.StartTime)
+		}
+		if !result.EndTime.IsZero() {
+			summary.EndTime = timestamppb.New(result.EndTime)
+		}
+		resp.Jobs = append(resp.Jobs, summary)
+	}
+	return resp, nil
+}
+
+// loadUserMap helper func to read the administrator's mapping of users to local users
+func loadUserMap(path string) (*joblib.UserMap, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	var users joblib.UserMap
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, err
+	}
+	if err := users.Validate(); err != nil {
+		return nil, err
+	}
+	return &users, nil
+}
+
+// loadSeccompPolicy helper func to read the administrator's minimum seccomp
+// profiles, without one jobs are only filtered when they ask for a profile
+func loadSeccompPolicy(path string) (joblib.SeccompPolicy, error) {
+	var policy joblib.SeccompPolicy
+	if path == "" {
+		return policy, nil
+	}
 
-	fmt.Printf("job status is %s", myJob.Status())
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return policy, err
+	}
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return policy, err
+	}
+	return policy, policy.Validate()
+}
 
-	return &worker.WorkerQueryResponse{Status: myJob.Status()}, nil
+// loadNamespacePolicy helper func to read the administrator's namespace policy,
+// without one jobs share the host's namespaces unless they ask otherwise
+func loadNamespacePolicy(path string) (joblib.NamespacePolicy, error) {
+	var policy joblib.NamespacePolicy
+	if path == "" {
+		return policy, nil
+	}
 
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return policy, err
+	}
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
+
+// setupImages() helper func to find the images folder and prepare the folder of
+// the jobs' layers under dataDir. Returns nil when images are disabled. The
+// layers are mounted as root, so they are never kept in a shared folder like
+// the temp folder where other users could swap them for symlinks
+func setupImages(dir string, dataDir string) (*joblib.Images, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if dataDir == "" {
+		return nil, fmt.Errorf("images need --data-dir to keep the jobs' layers in")
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	layerDir, err := filepath.Abs(filepath.Join(dataDir, "layers"))
+	if err != nil {
+		return nil, err
+	}
+	images := &joblib.Images{Dir: dir, LayerDir: layerDir}
+	if err := images.Setup(); err != nil {
+		return nil, err
+	}
+	return images, nil
 }
 
 func main() {
+	// jobs that need their process set up are started through this binary
+	joblib.RunChild()
 	flag.Parse()
 
 	log.Printf("server starting on port %d...\n", *port)
@@ -161,9 +822,85 @@ func main() {
 		Certificates: []tls.Certificate{cert},
 		ClientCAs:    ca,
 	}
+	var cgroup *joblib.CGroup
+	if *cgroupRoot != "" {
+		cgroup = joblib.NewCGroup(*cgroupRoot)
+		if err := cgroup.Setup(); err != nil {
+			log.Fatalf("failed to setup cgroup %q: %v", *cgroupRoot, err)
+		}
+	}
+
+	limitPolicy, err := loadLimitPolicy(*limitsPath)
+	if err != nil {
+		log.Fatalf("failed to load limits %q: %v", *limitsPath, err)
+	}
+
+	users, err := loadUserMap(*usersPath)
+	if err != nil {
+		log.Fatalf("failed to load users %q: %v", *usersPath, err)
+	}
+
+	nsPolicy, err := loadNamespacePolicy(*nsPath)
+	if err != nil {
+		log.Fatalf("failed to load namespaces %q: %v", *nsPath, err)
+	}
+
+	seccompPolicy, err := loadSeccompPolicy(*seccompPath)
+	if err != nil {
+		log.Fatalf("failed to load seccomp policy %q: %v", *seccompPath, err)
+	}
+
+	commands := &commandPolicy{path: *commandPath}
+	if err := commands.reload(); err != nil {
+		log.Fatalf("failed to load commands %q: %v", *commandPath, err)
+	}
+	go reloadOnHangup(commands)
+
+	output := joblib.OutputOptions{MaxSize: *outputMax, Policy: joblib.OutputPolicy(*outputMode)}
+	if *dataDir != "" {
+		output.Dir = filepath.Join(*dataDir, "output")
+	}
+	if err := output.Validate(); err != nil {
+		log.Fatalf("invalid output options: %v", err)
+	}
+
 	jw := joblib.NewJobWorker()
-	grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
-	worker.RegisterWorkerServer(grpcServer, &workerServer{JobWorker: jw})
+	if *dataDir != "" {
+		store, err := joblib.NewLogStore(*dataDir)
+		if err != nil {
+			log.Fatalf("failed to open job store %q: %v", *dataDir, err)
+		}
+		defer store.Close()
+		jw, err = joblib.NewJobWorkerWithStore(store, cgroup)
+		if err != nil {
+			log.Fatalf("failed to load jobs from %q: %v", *dataDir, err)
+		}
+	}
+	retention := joblib.Retention{MaxAge: *keepFor, MaxJobs: *keepJobs}
+	if err := retention.Validate(); err != nil {
+		log.Fatalf("invalid retention: %v", err)
+	}
+	if retention != (joblib.Retention{}) {
+		// a restart replays only what is left
+		pruneJobs(jw, retention)
+		go func() {
+			for range time.Tick(pruneInterval) {
+				pruneJobs(jw, retention)
+			}
+		}()
+	}
+	// the layers of lost jobs are only removed once their processes were killed
+	images, err := setupImages(*imagesDir, *dataDir)
+	if err != nil {
+		log.Fatalf("failed to setup images %q: %v", *imagesDir, err)
+	}
+	// every rpc is authenticated by the interceptors before its handler runs
+	grpcServer := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpc.UnaryInterceptor(unaryAuthInterceptor),
+		grpc.StreamInterceptor(streamAuthInterceptor),
+	)
+	worker.RegisterWorkerServer(grpcServer, &workerServer{JobWorker: jw, CGroup: cgroup, LimitPolicy: limitPolicy, Output: output, Users: users, NamespacePolicy: nsPolicy, SeccompPolicy: seccompPolicy, Images: images, Commands: commands})
 	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", *port))
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+
+	worker "github.com/sbui-dev/jobworker/data/proto"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestJobStopTimeout(t *testing.T) {
+	w, aliceJob, _ := testServer(t)
+	ctx := principalContext("alice", RoleUser)
+
+	for _, seconds := range []int64{-1, 301, 1 << 62} {
+		_, err := w.JobStop(ctx, &worker.WorkerStopRequest{JobId: aliceJob.JobID, TimeoutSeconds: seconds})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "timeout of %d seconds", seconds)
+	}
+
+	// a pending job is stopped straight away, a timeout within the cap is accepted
+	_, err := w.JobStop(ctx, &worker.WorkerStopRequest{JobId: aliceJob.JobID, TimeoutSeconds: 300})
+	assert.Nil(t, err)
+}